@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks"
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+// runAdd executes the add command
+func runAdd(info *appInfo, args []string) error {
+	var date string
+	var file string
+	var cfgOpts configOpts
+	var srcOpts sourceOpts
+	forced := make(map[sources.Type]*bool)
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("%s add appends a task to a task source file\n", info.name)
+		fmt.Print("\nUsage:\n")
+		fmt.Printf("  %s add [flags] \"<date>: <text>\"\n", info.name)
+		fmt.Printf("\nArgs:\n")
+		fmt.Printf("  task\t\t task line, with the type detected from its date unless a type flag is passed\n")
+		fmt.Printf("\t\t relative dates (today, tomorrow, next <weekday>, next week, in <n> days|weeks|months) add a single task\n")
+		fmt.Printf("\nFlags:\n")
+		fmt.Printf("      --weekly\t add a weekly task\n")
+		fmt.Printf("      --monthly\t add a monthly task\n")
+		fmt.Printf("      --annual\t add an annual task\n")
+		fmt.Printf("      --single\t add a single task\n")
+		fmt.Printf("      --floating add a floating task\n")
+		fmt.Printf("      --deadline add a deadline task\n")
+		fmt.Printf("      --relative add a relative task\n")
+		fmt.Printf("  -f, --file\t source file to which the task is added \tdefault: first configured source file of the task's type\n")
+		fmt.Printf("  -d, --date\t date in YYYY-MM-DD format for relative dates \tdefault: today\n")
+		fmt.Printf("      --profile\t name of the config file profile to use\n")
+		fmt.Printf("      --config\t path to the config file\n")
+	}
+	for _, typ := range []sources.Type{sources.TypeWeekly, sources.TypeMonthly, sources.TypeAnnual, sources.TypeSingle, sources.TypeFloating, sources.TypeDeadline, sources.TypeRelative} {
+		forced[typ] = fs.Bool(string(typ), false, fmt.Sprintf("add a %s task", typ))
+	}
+	fs.StringVar(&file, "f", "", "source file to which the task is added")
+	fs.StringVar(&file, "file", "", "source file to which the task is added")
+	fs.StringVar(&date, "d", "", "date for relative dates (YYYY-MM-DD)")
+	fs.StringVar(&date, "date", "", "date for relative dates (YYYY-MM-DD)")
+	cfgOpts.addFlags(fs)
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("missing task to add")
+	}
+	// allow the task to be passed without quotes
+	line := strings.Join(fs.Args(), " ")
+
+	typ := sources.TypeAuto
+	for _, t := range sources.Types {
+		if forced[t] == nil || !*forced[t] {
+			continue
+		}
+		if typ != sources.TypeAuto {
+			return fmt.Errorf("invalid flags: --%s and --%s cannot both be passed", typ, t)
+		}
+		typ = t
+	}
+
+	now, err := parseDate(date)
+	if err != nil {
+		return err
+	}
+	now = fixDate(now)
+
+	entry, err := sources.NewEntry(line, typ, now)
+	if err != nil {
+		return err
+	}
+	if entry.Type == sources.TypeSingle && entry.Days < 0 {
+		return fmt.Errorf("single task [%s] is in the past", entry)
+	}
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	// a file passed with --file can be used without any configured sources
+	err = srcOpts.load(cfg.Sources)
+	if err != nil && file == "" {
+		return err
+	}
+	loader := tasks.NewLoader(nil, nil)
+	srcOpts.addTo(loader)
+
+	fp, fileTyp, err := addTarget(loader, file, entry.Type)
+	if err != nil {
+		return err
+	}
+	err = appendEntry(fp, fileTyp, entry)
+	if err != nil {
+		return err
+	}
+
+	// a relative task has no dates of its own until it is resolved to the tasks to which it refers
+	if entry.Type == sources.TypeRelative {
+		fmt.Printf("added [%s] to %s\n", entry, fp)
+		return nil
+	}
+	next := now.AddDate(0, 0, entry.Days)
+	fmt.Printf("added [%s] to %s: next on %s (%s)\n", entry, fp, next.Format(printTimeFormat), daysFromNow(entry.Days))
+	return nil
+}
+
+// addTarget returns the source file to which a task of the specified type is added along with the type
+// of the file, which is the file passed with --file or the first configured file of the task's type
+// or, if there is none, the first mixed source file
+func addTarget(loader *tasks.Loader, file string, typ sources.Type) (string, sources.Type, error) {
+	if file != "" {
+		files, err := loader.Files(file)
+		if err != nil {
+			return "", "", err
+		}
+		for _, t := range sources.Types {
+			if len(files[t]) > 0 {
+				return file, t, nil
+			}
+		}
+		return file, sources.TypeAuto, nil
+	}
+
+	files, err := loader.Files()
+	if err != nil {
+		return "", "", err
+	}
+	if len(files[typ]) > 0 {
+		return files[typ][0], typ, nil
+	}
+	if len(files[sources.TypeAuto]) > 0 {
+		return files[sources.TypeAuto][0], sources.TypeAuto, nil
+	}
+	return "", "", fmt.Errorf("no %s or mixed source file is configured: use --file to specify the file to which the task is added", typ)
+}
+
+// appendEntry appends a task to a source file
+func appendEntry(fp string, typ sources.Type, entry *sources.Entry) error {
+	return updateFile(fp, typ, func(f *sources.File) error {
+		f.Append(entry.String(), entry.Type)
+		return nil
+	})
+}
+
+// daysFromNow describes a number of days from now
+func daysFromNow(days int) string {
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "tomorrow"
+	}
+	return fmt.Sprintf("in %d days", days)
+}
@@ -8,6 +8,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
 )
 
 const (
@@ -16,6 +18,12 @@ const (
 	envMonthlySources = "CALENDAR_TASKS_MONTHLY_SOURCES"
 	envAnnualSources  = "CALENDAR_TASKS_ANNUAL_SOURCES"
 	envSingleSources  = "CALENDAR_TASKS_SINGLE_SOURCES"
+	envMixedSources   = "CALENDAR_TASKS_SOURCES"
+	envInclude        = "CALENDAR_TASKS_INCLUDE"
+	envExclude        = "CALENDAR_TASKS_EXCLUDE"
+	envConfig         = "CALENDAR_TASKS_CONFIG"
+	envProfile        = "CALENDAR_TASKS_PROFILE"
+	envDataDir        = "CALENDAR_TASKS_DATA_DIR"
 
 	// format for date flag input
 	inputDateFormat = "2006-01-02"
@@ -24,61 +32,127 @@ const (
 type cliOpts struct {
 	days         int
 	back         int
+	overdue      int
 	date         time.Time
+	noColor      bool
+	keepGoing    bool
+	showIDs      bool
+	showSources  bool
+	hideDone     bool
+	listTags     bool
+	minPriority  int
 	printVersion bool
 
+	tags        listFlag
+	excludeTags listFlag
+	contexts    listFlag
+	labels      listFlag
+
+	sourceOpts
+}
+
+type sourceOpts struct {
 	weeklySources  []string
 	monthlySources []string
 	annualSources  []string
 	singleSources  []string
+	mixedSources   []string
+
+	include []string
+	exclude []string
 }
 
-func parseArgs(argsIn []string, opts *cliOpts) error {
+func parseArgs(info *appInfo, argsIn []string, opts *cliOpts) error {
 	var date string
+	var minPriority string
+	var cfgOpts configOpts
 
-	flag.StringVar(&date, "d", "", "starting date (YYY-MM-DD)")
-	flag.StringVar(&date, "date", "", "starting date (YYY-MM-DD)")
-	flag.IntVar(&opts.back, "b", 0, "number of days back from today")
-	flag.IntVar(&opts.back, "back", 0, "number of days back from today")
-	flag.BoolVar(&opts.printVersion, "v", false, "display version information")
-	flag.BoolVar(&opts.printVersion, "version", false, "display version information")
-	flag.Parse()
+	fs := flag.NewFlagSet(info.name, flag.ExitOnError)
+	fs.Usage = info.setUsage()
+	fs.StringVar(&date, "d", "", "starting date (YYY-MM-DD)")
+	fs.StringVar(&date, "date", "", "starting date (YYY-MM-DD)")
+	fs.IntVar(&opts.back, "b", 0, "number of days back from today")
+	fs.IntVar(&opts.back, "back", 0, "number of days back from today")
+	fs.IntVar(&opts.overdue, "overdue", 0, "number of days back from today to show overdue tasks")
+	fs.BoolVar(&opts.noColor, "no-color", false, "disable colored output")
+	fs.BoolVar(&opts.keepGoing, "k", false, "continue past errors in source files")
+	fs.BoolVar(&opts.keepGoing, "keep-going", false, "continue past errors in source files")
+	fs.BoolVar(&opts.showIDs, "ids", false, "display task identifiers")
+	fs.BoolVar(&opts.showSources, "sources", false, "display the source file and line of each task")
+	fs.BoolVar(&opts.hideDone, "hide-done", false, "hide tasks marked as done")
+	fs.Var(&opts.tags, "tag", "show only tasks with any of the tags")
+	fs.Var(&opts.excludeTags, "exclude-tag", "hide tasks with any of the tags")
+	fs.Var(&opts.contexts, "context", "show only tasks with any of the contexts")
+	fs.Var(&opts.labels, "label", "show only tasks from source files with any of the labels")
+	fs.StringVar(&minPriority, "min-priority", "", "show only tasks with at least the priority")
+	fs.BoolVar(&opts.listTags, "list-tags", false, "list the tags and contexts of tasks instead of the tasks")
+	fs.BoolVar(&opts.printVersion, "v", false, "display version information")
+	fs.BoolVar(&opts.printVersion, "version", false, "display version information")
+	cfgOpts.addFlags(fs)
+	err := fs.Parse(argsIn[1:])
+	if err != nil {
+		return err
+	}
 
 	if opts.printVersion {
 		return nil
 	}
 
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+
+	// apply defaults from the config file for flags that were not set explicitly
+	setFlags := visitedFlags(fs)
+	if cfg.Defaults.Back != nil && !setFlags["b"] && !setFlags["back"] {
+		opts.back = *cfg.Defaults.Back
+	}
+	if cfg.Defaults.Overdue != nil && !setFlags["overdue"] {
+		opts.overdue = *cfg.Defaults.Overdue
+	}
+	if cfg.Defaults.Color != nil && !setFlags["no-color"] {
+		opts.noColor = !*cfg.Defaults.Color
+	}
+
 	if opts.back < 0 {
 		return fmt.Errorf("invalid negative value: --back %d", opts.back)
 	}
+	if opts.overdue < 0 {
+		return fmt.Errorf("invalid negative value: --overdue %d", opts.overdue)
+	}
 
-	if date == "" {
-		opts.date = time.Now()
-	} else {
-		var err error
-		opts.date, err = time.Parse(inputDateFormat, date)
+	opts.date, err = parseDate(date)
+	if err != nil {
+		return err
+	}
+
+	if minPriority != "" {
+		opts.minPriority, err = sources.ParsePriority(strings.ToUpper(minPriority))
 		if err != nil {
-			return fmt.Errorf("invalid date: --date %s does not match YYYY-MM-DD format", date)
+			return fmt.Errorf("invalid flag: --min-priority %v", err)
 		}
 	}
 
-	// parse environment variables
-	opts.weeklySources = parseStringSliceEnvVar(os.Getenv(envWeeklySources))
-	opts.monthlySources = parseStringSliceEnvVar(os.Getenv(envMonthlySources))
-	opts.annualSources = parseStringSliceEnvVar(os.Getenv(envAnnualSources))
-	opts.singleSources = parseStringSliceEnvVar(os.Getenv(envSingleSources))
-	if (len(opts.weeklySources) + len(opts.monthlySources) + len(opts.annualSources) + len(opts.singleSources)) == 0 {
-		return errors.New("no source files provided, use --help for usage")
+	err = opts.sourceOpts.load(cfg.Sources)
+	if err != nil {
+		return err
 	}
 
 	// run with defaults
-	if flag.NArg() == 0 {
+	if fs.NArg() == 0 {
 		opts.days = 0
+		if cfg.Defaults.Days != nil {
+			opts.days = *cfg.Defaults.Days
+		}
+		if opts.days < 0 {
+			return fmt.Errorf("invalid negative value: days %d", opts.days)
+		}
 		return nil
 	}
 
 	// parse day arg
-	dayStr := flag.Arg(0)
+	dayStr := fs.Arg(0)
 	days, err := strconv.Atoi(dayStr)
 	if err != nil {
 		return fmt.Errorf("unparsable integer argument: %s", dayStr)
@@ -91,6 +165,75 @@ func parseArgs(argsIn []string, opts *cliOpts) error {
 	return nil
 }
 
+// listFlag is a flag that can be repeated or passed a comma-separated list of values
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(s string) error {
+	*l = append(*l, parseStringSliceEnvVar(s)...)
+	return nil
+}
+
+// parseInterspersed parses flags that can be passed before or after positional arguments, returning
+// the positional arguments
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		err := fs.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// parseDate parses a date flag, defaulting to the current date if the flag is empty
+func parseDate(date string) (time.Time, error) {
+	if date == "" {
+		return time.Now(), nil
+	}
+	t, err := time.Parse(inputDateFormat, date)
+	if err != nil {
+		return t, fmt.Errorf("invalid date: --date %s does not match YYYY-MM-DD format", date)
+	}
+	return t, nil
+}
+
+// load populates the source files from a config, which has already applied environment variables
+func (opts *sourceOpts) load(cfg sourceConfig) error {
+	opts.weeklySources = cfg.Weekly
+	opts.monthlySources = cfg.Monthly
+	opts.annualSources = cfg.Annual
+	opts.singleSources = cfg.Single
+	opts.mixedSources = cfg.Mixed
+	opts.include = cfg.Include
+	opts.exclude = cfg.Exclude
+	if (len(opts.weeklySources) + len(opts.monthlySources) + len(opts.annualSources) + len(opts.singleSources) + len(opts.mixedSources)) == 0 {
+		return errors.New("no source files provided, use --help for usage")
+	}
+	return nil
+}
+
+// parseStringSliceEnvVarOr parses a comma-separated environment variable into a slice of string,
+// returning the fallback if the environment variable is not set
+func parseStringSliceEnvVarOr(env string, fallback []string) []string {
+	if parsed := parseStringSliceEnvVar(os.Getenv(env)); len(parsed) > 0 {
+		return parsed
+	}
+	if fallback == nil {
+		return []string{}
+	}
+	return fallback
+}
+
 // parseStringSliceEnvVar parses a comma-separated environment variable into a slice of string
 func parseStringSliceEnvVar(envStr string) []string {
 	parsed := []string{}
@@ -104,6 +247,15 @@ func parseStringSliceEnvVar(envStr string) []string {
 	return parsed
 }
 
+// visitedFlags returns the names of the flags that were explicitly set
+func visitedFlags(fs *flag.FlagSet) map[string]bool {
+	visited := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		visited[f.Name] = true
+	})
+	return visited
+}
+
 type appInfo struct {
 	name    string
 	version string
@@ -112,18 +264,53 @@ type appInfo struct {
 func (info *appInfo) setUsage() func() {
 	return func() {
 		fmt.Printf("%s displays upcoming scheduled tasks\n", info.name)
-		fmt.Printf("\nTasks are read from files specified in comma-separated environment variables:\n")
+		fmt.Printf("\nTasks are read from files specified in the config file (%s) or in comma-separated environment variables:\n", displayConfigPath())
 		fmt.Printf("  %s\t\tsource files for weekly tasks\t\tex: %s=\"file1,file2,...\"\n", envWeeklySources, envWeeklySources)
 		fmt.Printf("  %s\tsource files for monthly tasks\t\tex: %s=\"file1,file2,...\"\n", envMonthlySources, envMonthlySources)
 		fmt.Printf("  %s\t\tsource files for annual tasks\t\tex: %s=\"file1,file2,...\"\n", envAnnualSources, envAnnualSources)
 		fmt.Printf("  %s\t\tsource files for single tasks\t\tex: %s=\"file1,file2,...\"\n", envSingleSources, envSingleSources)
+		fmt.Printf("  %s\t\t\tsource files for tasks of any type\tex: %s=\"file1,file2,...\"\n", envMixedSources, envMixedSources)
+		fmt.Printf("Sources can be files, directories, or globs (including ** for recursive matching), with discovered files filtered by:\n")
+		fmt.Printf("  %s\t\t\tpatterns of files to include\t\tex: %s=\"*.txt,*.tasks\"\n", envInclude, envInclude)
+		fmt.Printf("  %s\t\t\tpatterns of files to exclude\t\tex: %s=\"*.bak,**/archive/**\"\n", envExclude, envExclude)
+		fmt.Printf("Tasks marked as done and snoozed tasks are stored in a data directory (%s) that can be set with:\n", displayDataDir())
+		fmt.Printf("  %s\t\tdirectory for data\t\t\tex: %s=\"~/tasks/data\"\n", envDataDir, envDataDir)
 		fmt.Print("\nUsage:\n")
 		fmt.Printf("  %s [flags] [args]\n", info.name)
+		fmt.Printf("  %s <command> [flags] [args]\n", info.name)
+		fmt.Printf("\nCommands:\n")
+		fmt.Printf("  add\t\t append a task to a task source file\n")
+		fmt.Printf("  config migrate\t write source files from environment variables to the config file\n")
+		fmt.Printf("  done\t\t mark an occurrence of a task as done\n")
+		fmt.Printf("  edit\t\t change the text or dates of a task\n")
+		fmt.Printf("  explain\t describe why a task does or does not appear on a date\n")
+		fmt.Printf("  fmt\t\t rewrite task source files in canonical form\n")
+		fmt.Printf("  lint\t\t check task source files for problems\n")
+		fmt.Printf("  prune\t\t remove past single tasks from task source files and archive them\n")
+		fmt.Printf("  rm\t\t remove tasks from task source files\n")
+		fmt.Printf("  search\t\t find tasks by fuzzy matching of their text\n")
+		fmt.Printf("  snooze\t\t move an occurrence of a task to another date\n")
+		fmt.Printf("  stats\t\t display completion streaks and rates of weekly and monthly tasks\n")
+		fmt.Printf("  when\t\t list the next occurrences of tasks matching a regular expression\n")
 		fmt.Printf("\nArgs:\n")
 		fmt.Printf("  days int\t number of days from date to get tasks \t\tdefault: 0 (today)\n")
 		fmt.Printf("\nFlags:\n")
 		fmt.Printf("  -b, --back\t number of days back from date to get tasks \tdefault: 0 (none)\n")
 		fmt.Printf("  -d, --date\t date in YYYY-MM-DD format \t\t\tdefault: today\n")
+		fmt.Printf("      --overdue\t number of days back from date to show tasks not marked as done \tdefault: 0 (none)\n")
+		fmt.Printf("  -k, --keep-going continue past errors in source files and report them at the end\n")
+		fmt.Printf("      --ids\t display the identifier of each task for use with rm, edit, and done\n")
+		fmt.Printf("      --sources\t display the source file and line of each task\n")
+		fmt.Printf("      --hide-done hide tasks marked as done instead of marking them with %s\n", doneMark)
+		fmt.Printf("      --tag\t show only tasks with any of the comma-separated #tags (repeatable)\n")
+		fmt.Printf("      --exclude-tag hide tasks with any of the comma-separated #tags (repeatable)\n")
+		fmt.Printf("      --context\t show only tasks with any of the comma-separated @contexts (repeatable)\n")
+		fmt.Printf("      --label\t show only tasks from source files with any of the comma-separated labels (repeatable)\n")
+		fmt.Printf("      --min-priority show only tasks with at least the priority (A-Z, !! for A, or ! for B)\n")
+		fmt.Printf("      --list-tags list the #tags and @contexts of the tasks in the days instead of the tasks\n")
+		fmt.Printf("      --no-color disable colored output\n")
+		fmt.Printf("      --profile\t name of the config file profile to use\n")
+		fmt.Printf("      --config\t path to the config file\n")
 		fmt.Printf("  -h, --help\t display usage information\n")
 		fmt.Printf("  -v, --version\t display version information\n")
 	}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestParseInterspersed(t *testing.T) {
+	tests := map[string]struct {
+		args               []string
+		expectedPositional []string
+		expectedDate       string
+		expectedUndo       bool
+	}{
+		"no arguments": {
+			args:               []string{},
+			expectedPositional: []string{},
+		},
+		"flags before positional arguments": {
+			args:               []string{"--date", "2024-05-13", "--undo", "abc123", "def456"},
+			expectedPositional: []string{"abc123", "def456"},
+			expectedDate:       "2024-05-13",
+			expectedUndo:       true,
+		},
+		"flags after positional arguments": {
+			args:               []string{"abc123", "--date", "2024-05-13"},
+			expectedPositional: []string{"abc123"},
+			expectedDate:       "2024-05-13",
+		},
+		"flags between positional arguments": {
+			args:               []string{"abc123", "-d", "2024-05-13", "def456", "--undo"},
+			expectedPositional: []string{"abc123", "def456"},
+			expectedDate:       "2024-05-13",
+			expectedUndo:       true,
+		},
+		"arguments after terminator are positional": {
+			args:               []string{"abc123", "--", "--undo"},
+			expectedPositional: []string{"abc123", "--undo"},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var date string
+			var undo bool
+			fs := flag.NewFlagSet("done", flag.ContinueOnError)
+			fs.StringVar(&date, "d", "", "date")
+			fs.StringVar(&date, "date", "", "date")
+			fs.BoolVar(&undo, "undo", false, "undo")
+
+			positional, err := parseInterspersed(fs, test.args)
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if !reflect.DeepEqual(positional, test.expectedPositional) {
+				t.Fatalf("positional arguments %v not equal to expected %v", positional, test.expectedPositional)
+			}
+			if date != test.expectedDate {
+				t.Fatalf("date %q not equal to expected %q", date, test.expectedDate)
+			}
+			if undo != test.expectedUndo {
+				t.Fatalf("undo %t not equal to expected %t", undo, test.expectedUndo)
+			}
+		})
+	}
+}
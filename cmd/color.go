@@ -11,24 +11,52 @@ const (
 	gray   = "\033[37m"
 	yellow = "\033[33m"
 	blue   = "\033[34m"
+	red    = "\033[31m"
+	purple = "\033[35m"
+	green  = "\033[32m"
+	cyan   = "\033[36m"
 )
 
 type color string
 
 var (
-	colorReset  color = reset
-	colorToday  color = yellow
-	colorPast   color = blue
-	colorFuture color = gray
+	colorReset    color = reset
+	colorToday    color = yellow
+	colorPast     color = blue
+	colorFuture   color = gray
+	colorOverdue  color = red
+	colorPriority color = purple
+	colorReminder color = green
 )
 
+// fileColors maps the names of the colors that can be set in the header of a source file to colors
+var fileColors = map[string]color{
+	"red":    red,
+	"green":  green,
+	"yellow": yellow,
+	"blue":   blue,
+	"purple": purple,
+	"cyan":   cyan,
+	"gray":   gray,
+}
+
 // windows does not support color printing
 func init() {
 	if runtime.GOOS == "windows" {
-		colorReset = ""
-		colorToday = ""
-		colorPast = ""
-		colorFuture = ""
+		disableColor()
+	}
+}
+
+func disableColor() {
+	colorReset = ""
+	colorToday = ""
+	colorPast = ""
+	colorFuture = ""
+	colorOverdue = ""
+	colorPriority = ""
+	colorReminder = ""
+	for name := range fileColors {
+		fileColors[name] = ""
 	}
 }
 
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// name of the directory, relative to the user config directory, in which the config file is stored
+const configDirName = "calendar-tasks"
+
+// config is the contents of the config file, holding default settings and named profiles that
+// override them
+type config struct {
+	profileConfig
+	Profiles map[string]profileConfig `json:"profiles,omitempty"`
+}
+
+type profileConfig struct {
+	Sources  sourceConfig  `json:"sources"`
+	Defaults defaultConfig `json:"defaults"`
+}
+
+type sourceConfig struct {
+	Weekly  []string `json:"weekly,omitempty"`
+	Monthly []string `json:"monthly,omitempty"`
+	Annual  []string `json:"annual,omitempty"`
+	Single  []string `json:"single,omitempty"`
+	Mixed   []string `json:"mixed,omitempty"`
+
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+func (s sourceConfig) empty() bool {
+	return (len(s.Weekly) + len(s.Monthly) + len(s.Annual) + len(s.Single) + len(s.Mixed)) == 0
+}
+
+// withEnv returns the sources with those set in environment variables replacing them
+func (s sourceConfig) withEnv() sourceConfig {
+	return sourceConfig{
+		Weekly:  parseStringSliceEnvVarOr(envWeeklySources, s.Weekly),
+		Monthly: parseStringSliceEnvVarOr(envMonthlySources, s.Monthly),
+		Annual:  parseStringSliceEnvVarOr(envAnnualSources, s.Annual),
+		Single:  parseStringSliceEnvVarOr(envSingleSources, s.Single),
+		Mixed:   parseStringSliceEnvVarOr(envMixedSources, s.Mixed),
+		Include: parseStringSliceEnvVarOr(envInclude, s.Include),
+		Exclude: parseStringSliceEnvVarOr(envExclude, s.Exclude),
+	}
+}
+
+type defaultConfig struct {
+	Days    *int  `json:"days,omitempty"`
+	Back    *int  `json:"back,omitempty"`
+	Overdue *int  `json:"overdue,omitempty"`
+	Color   *bool `json:"color,omitempty"`
+}
+
+// resolve returns the settings for a named profile, falling back to the top level settings for any
+// values not set by the profile
+func (c *config) resolve(profile string) (*profileConfig, error) {
+	resolved := c.profileConfig
+	if profile == "" {
+		return &resolved, nil
+	}
+
+	p, ok := c.Profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile [%s] in config file", profile)
+	}
+	// a profile's sources replace the top level sources entirely so that sets of files are not mixed
+	if !p.Sources.empty() {
+		resolved.Sources = p.Sources
+	}
+	if p.Defaults.Days != nil {
+		resolved.Defaults.Days = p.Defaults.Days
+	}
+	if p.Defaults.Back != nil {
+		resolved.Defaults.Back = p.Defaults.Back
+	}
+	if p.Defaults.Overdue != nil {
+		resolved.Defaults.Overdue = p.Defaults.Overdue
+	}
+	if p.Defaults.Color != nil {
+		resolved.Defaults.Color = p.Defaults.Color
+	}
+	return &resolved, nil
+}
+
+// readConfig reads a config file, returning an empty config if the file does not exist
+func readConfig(path string) (*config, error) {
+	cfg := &config{}
+	b, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// writeConfig writes a config file, creating its directory if necessary
+func writeConfig(path string, cfg *config) error {
+	b, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o750)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o600)
+}
+
+// configOpts holds the flags used to locate the config file and select a profile from it
+type configOpts struct {
+	path    string
+	profile string
+}
+
+func (c *configOpts) addFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.path, "config", "", "path to the config file")
+	fs.StringVar(&c.profile, "profile", "", "name of the config file profile to use")
+}
+
+// load reads the config file and resolves the selected profile. Source files set in environment
+// variables take precedence over those in the config file, except for those of a selected profile, so
+// that settings are taken from flags, then the profile, then environment variables, then the defaults
+// of the config file.
+func (c *configOpts) load() (*profileConfig, error) {
+	path, err := c.configPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	profile := c.profileName()
+	resolved, err := cfg.resolve(profile)
+	if err != nil {
+		return nil, err
+	}
+	if profile == "" || cfg.Profiles[profile].Sources.empty() {
+		resolved.Sources = resolved.Sources.withEnv()
+	}
+	return resolved, nil
+}
+
+// configPath returns the path to the config file from the flag, the environment, or the default
+// location in the user config directory, in that order of precedence
+func (c *configOpts) configPath() (string, error) {
+	if c.path != "" {
+		return c.path, nil
+	}
+	if path := os.Getenv(envConfig); path != "" {
+		return path, nil
+	}
+	// os.UserConfigDir only respects XDG_CONFIG_HOME on Unix systems other than macOS
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, configDirName, "config.json"), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine config file location: %v", err)
+	}
+	return filepath.Join(dir, configDirName, "config.json"), nil
+}
+
+func (c *configOpts) profileName() string {
+	if c.profile != "" {
+		return c.profile
+	}
+	return os.Getenv(envProfile)
+}
+
+// displayConfigPath returns the default config file path for display in usage information
+func displayConfigPath() string {
+	path, err := (&configOpts{}).configPath()
+	if err != nil {
+		return filepath.Join("$XDG_CONFIG_HOME", configDirName, "config.json")
+	}
+	return path
+}
+
+// runConfig executes the config command
+func runConfig(info *appInfo, args []string) error {
+	usage := fmt.Sprintf("usage: %s config migrate [--profile name] [--config path] [--force]", info.name)
+	if len(args) < 2 || args[1] != "migrate" {
+		return errors.New(usage)
+	}
+
+	var cfgOpts configOpts
+	var force bool
+	fs := flag.NewFlagSet("config migrate", flag.ExitOnError)
+	fs.Usage = func() { fmt.Println(usage) }
+	cfgOpts.addFlags(fs)
+	fs.BoolVar(&force, "force", false, "overwrite existing sources in the config file")
+	err := fs.Parse(args[2:])
+	if err != nil {
+		return err
+	}
+
+	return migrateConfig(&cfgOpts, force)
+}
+
+// migrateConfig writes the source files set in environment variables to the config file
+func migrateConfig(cfgOpts *configOpts, force bool) error {
+	sources := sourceConfig{
+		Weekly:  parseStringSliceEnvVar(os.Getenv(envWeeklySources)),
+		Monthly: parseStringSliceEnvVar(os.Getenv(envMonthlySources)),
+		Annual:  parseStringSliceEnvVar(os.Getenv(envAnnualSources)),
+		Single:  parseStringSliceEnvVar(os.Getenv(envSingleSources)),
+		Mixed:   parseStringSliceEnvVar(os.Getenv(envMixedSources)),
+	}
+	if sources.empty() {
+		return errors.New("no source files set in environment variables, nothing to migrate")
+	}
+	// relative paths in environment variables are resolved from the working directory, which is
+	// not meaningful for a config file
+	for _, paths := range [][]string{sources.Weekly, sources.Monthly, sources.Annual, sources.Single, sources.Mixed} {
+		for i, p := range paths {
+			abs, err := filepath.Abs(p)
+			if err != nil {
+				return err
+			}
+			paths[i] = abs
+		}
+	}
+
+	path, err := cfgOpts.configPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := readConfig(path)
+	if err != nil {
+		return err
+	}
+
+	profile := cfgOpts.profileName()
+	target := cfg.profileConfig
+	if profile != "" {
+		target = cfg.Profiles[profile]
+	}
+	if !target.Sources.empty() && !force {
+		return fmt.Errorf("config file %s already has sources, use --force to overwrite", path)
+	}
+	sources.Include = parseStringSliceEnvVar(os.Getenv(envInclude))
+	sources.Exclude = parseStringSliceEnvVar(os.Getenv(envExclude))
+	target.Sources = sources
+
+	if profile == "" {
+		cfg.profileConfig = target
+	} else {
+		if cfg.Profiles == nil {
+			cfg.Profiles = make(map[string]profileConfig)
+		}
+		cfg.Profiles[profile] = target
+	}
+
+	err = writeConfig(path, cfg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("wrote config file %s\n", path)
+	return nil
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestConfigLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "calendar-tasks", "config.json")
+	days := 3
+	err := writeConfig(path, &config{
+		profileConfig: profileConfig{Sources: sourceConfig{Weekly: []string{"top.txt"}}},
+		Profiles: map[string]profileConfig{
+			"work":     {Sources: sourceConfig{Weekly: []string{"work.txt"}}},
+			"defaults": {Defaults: defaultConfig{Days: &days}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		env      string
+		profile  string
+		expected []string
+	}{
+		"config file": {
+			expected: []string{"top.txt"},
+		},
+		"environment variable overrides config file": {
+			env:      "env.txt",
+			expected: []string{"env.txt"},
+		},
+		"profile overrides environment variable": {
+			env:      "env.txt",
+			profile:  "work",
+			expected: []string{"work.txt"},
+		},
+		"environment variable overrides config file for profile without sources": {
+			env:      "env.txt",
+			profile:  "defaults",
+			expected: []string{"env.txt"},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(envWeeklySources, test.env)
+			t.Setenv(envProfile, "")
+			t.Setenv("XDG_CONFIG_HOME", dir)
+
+			cfg, err := (&configOpts{profile: test.profile}).load()
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.Sources.Weekly, test.expected) {
+				t.Fatalf("result sources %v not equal to expected sources %v", cfg.Sources.Weekly, test.expected)
+			}
+		})
+	}
+}
+
+func TestConfigPathXDG(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(envConfig, "")
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	path, err := (&configOpts{}).configPath()
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	expected := filepath.Join(dir, configDirName, "config.json")
+	if path != expected {
+		t.Fatalf("result path %s not equal to expected path %s", path, expected)
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/completions"
+	"github.com/dkaslovsky/calendar-tasks/pkg/snooze"
+)
+
+// names of the files, relative to the data directory, in which completed and snoozed tasks are logged
+const (
+	doneLogName     = "done.log"
+	snoozeStoreName = "snooze.log"
+)
+
+// dataDir returns the directory in which data such as completed tasks is stored
+func dataDir() (string, error) {
+	if dir := os.Getenv(envDataDir); dir != "" {
+		return dir, nil
+	}
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, configDirName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine data directory location: %v", err)
+	}
+	return filepath.Join(home, ".local", "share", configDirName), nil
+}
+
+// openDoneLog opens the log of completed tasks
+func openDoneLog() (*completions.Log, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return nil, err
+	}
+	return completions.Open(filepath.Join(dir, doneLogName))
+}
+
+// openSnoozeStore opens the store of snoozed tasks
+func openSnoozeStore() (*snooze.Store, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return nil, err
+	}
+	return snooze.Open(filepath.Join(dir, snoozeStoreName))
+}
+
+// displayDataDir returns the data directory for display in usage information
+func displayDataDir() string {
+	dir, err := dataDir()
+	if err != nil {
+		return filepath.Join("$XDG_DATA_HOME", configDirName)
+	}
+	return dir
+}
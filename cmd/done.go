@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks"
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+// maximum number of days back from today to search for the occurrence of a task marked as done
+const maxDoneLookback = 366
+
+// runDone executes the done command
+func runDone(info *appInfo, args []string) error {
+	var date string
+	var undo bool
+	var cfgOpts configOpts
+	var srcOpts sourceOpts
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("%s done marks an occurrence of a task as done\n", info.name)
+		fmt.Print("\nUsage:\n")
+		fmt.Printf("  %s done [flags] <id>...\n", info.name)
+		fmt.Printf("\nArgs:\n")
+		fmt.Printf("  id\t\t identifier of a task, displayed with the --ids flag\n")
+		fmt.Printf("\nFlags:\n")
+		fmt.Printf("  -d, --date\t date on which the occurrence is displayed in YYYY-MM-DD format \tdefault: most recent occurrence\n")
+		fmt.Printf("\t\t or, for floating tasks, date on which the task was done \t\tdefault: today\n")
+		fmt.Printf("      --undo\t mark the occurrence as not done\n")
+		fmt.Printf("      --profile\t name of the config file profile to use\n")
+		fmt.Printf("      --config\t path to the config file\n")
+	}
+	fs.StringVar(&date, "d", "", "date of the occurrence (YYYY-MM-DD)")
+	fs.StringVar(&date, "date", "", "date of the occurrence (YYYY-MM-DD)")
+	fs.BoolVar(&undo, "undo", false, "mark the occurrence as not done")
+	cfgOpts.addFlags(fs)
+	ids, err := parseInterspersed(fs, args[1:])
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return errors.New("missing identifier of task to mark as done")
+	}
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	err = srcOpts.load(cfg.Sources)
+	if err != nil {
+		return err
+	}
+	loader := tasks.NewLoader(nil, nil)
+	srcOpts.addTo(loader)
+
+	log, err := openDoneLog()
+	if err != nil {
+		return err
+	}
+	snoozed, err := openSnoozeStore()
+	if err != nil {
+		return err
+	}
+
+	// check every identifier before marking any occurrence as done
+	type doneTask struct {
+		id         string
+		text       string
+		occurrence time.Time
+	}
+	done := []doneTask{}
+	for _, id := range ids {
+		tsks, err := loader.Find(id)
+		if err != nil {
+			return err
+		}
+		if len(tsks) == 0 {
+			return fmt.Errorf("no task with identifier [%s]: the task may have been changed since its identifier was displayed", id)
+		}
+
+		occurrence, err := doneOccurrence(tsks, date, snoozed)
+		if err != nil {
+			return err
+		}
+		done = append(done, doneTask{id: id, text: tsks[0].String(), occurrence: occurrence})
+	}
+
+	status := "done"
+	if undo {
+		status = "not done"
+	}
+	for _, d := range done {
+		if undo {
+			err = log.Undo(d.id, d.occurrence, d.text)
+		} else {
+			err = log.Complete(d.id, d.occurrence, d.text)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("marked [%s] on %s as %s\n", d.text, d.occurrence.Format(printTimeFormat), status)
+	}
+	return nil
+}
+
+// doneOccurrence returns the original date of the occurrence of the tasks of a line to mark as done,
+// which is displayed on the date passed with --date or otherwise is the most recent occurrence
+// displayed on or before today, falling back to the next occurrence for tasks that have not yet occurred.
+// Floating tasks are instead marked as done on the date passed with --date or otherwise today, from
+// which their next occurrence is calculated.
+func doneOccurrence(tsks []tasks.Task, date string, overrides tasks.OverrideStore) (time.Time, error) {
+	if _, ok := tsks[0].(*sources.Floating); ok {
+		d, err := parseDate(date)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return fixDate(d), nil
+	}
+
+	if date != "" {
+		d, err := parseDate(date)
+		if err != nil {
+			return time.Time{}, err
+		}
+		d = fixDate(d)
+		for _, tsk := range tsks {
+			if o, ok := tasks.LastOccurrence(tsk, d, 0, overrides); ok {
+				return o.Date, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("task [%s] does not occur on %s", tsks[0], d.Format(printTimeFormat))
+	}
+
+	today := fixDate(time.Now())
+	var last tasks.Override
+	found := false
+	for _, tsk := range tsks {
+		o, ok := tasks.LastOccurrence(tsk, today, maxDoneLookback, overrides)
+		if ok && (!found || o.To.After(last.To)) {
+			last = o
+			found = true
+		}
+	}
+	if found {
+		return last.Date, nil
+	}
+
+	next := -1
+	for _, tsk := range tsks {
+		if days := tsk.DaysFrom(today); days >= 0 && (next < 0 || days < next) {
+			next = days
+		}
+	}
+	if next < 0 {
+		return time.Time{}, fmt.Errorf("task [%s] has no occurrence to mark as done: use --date to specify one", tsks[0])
+	}
+	return today.AddDate(0, 0, next), nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+// runEdit executes the edit command
+func runEdit(info *appInfo, args []string) error {
+	var dates string
+	var cfgOpts configOpts
+	var srcOpts sourceOpts
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("%s edit changes the text or dates of a task in its task source file\n", info.name)
+		fmt.Print("\nUsage:\n")
+		fmt.Printf("  %s edit [flags] <id> [text]\n", info.name)
+		fmt.Printf("\nArgs:\n")
+		fmt.Printf("  id\t\t identifier of a task, displayed with the --ids flag\n")
+		fmt.Printf("  text\t\t new text of the task \t\t\t\tdefault: unchanged\n")
+		fmt.Printf("\nFlags:\n")
+		fmt.Printf("      --dates\t new dates of the task, separated by '/' \tdefault: unchanged\n")
+		fmt.Printf("      --profile\t name of the config file profile to use\n")
+		fmt.Printf("      --config\t path to the config file\n")
+	}
+	fs.StringVar(&dates, "dates", "", "new dates of the task")
+	cfgOpts.addFlags(fs)
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("missing identifier of task to edit")
+	}
+	id := fs.Arg(0)
+	// allow the text to be passed without quotes
+	text := strings.Join(fs.Args()[1:], " ")
+	if text == "" && dates == "" {
+		return errors.New("missing new text or --dates of task to edit")
+	}
+
+	var newDates []string
+	var newReminders []int
+	var newLabel string
+	if dates != "" {
+		l, err := sources.Tokenize(dates + string(':'))
+		if err != nil {
+			return fmt.Errorf("invalid dates [%s]: %v", dates, err)
+		}
+		newDates = l.Dates
+		newReminders = l.Reminders
+		newLabel = l.Label
+	}
+
+	files, err := configuredFiles(cfgOpts, srcOpts)
+	if err != nil {
+		return err
+	}
+
+	var before, after string
+	src, err := updateTask(files, id, func(fl *sources.FileLine) error {
+		l, err := sources.Tokenize(fl.Text)
+		if err != nil {
+			return fmt.Errorf("invalid line [%s]: %v", fl.Text, err)
+		}
+		if newDates != nil {
+			l.Dates = newDates
+			l.Reminders = newReminders
+			// the label is kept unless replaced so that the tasks that refer to it still do
+			if newLabel != "" {
+				l.Label = newLabel
+			}
+		}
+		if text != "" {
+			l.Text = text
+		}
+
+		entry, err := sources.NewEntry(sources.FormatLine(l), fl.Type, fixDate(time.Now()))
+		if err != nil {
+			return err
+		}
+		before = fl.Text
+		after = entry.String()
+		fl.Replace(after)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("edited %s: [%s] -> [%s]\n", src, before, after)
+	return nil
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks"
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+// runExplain executes the explain command
+func runExplain(info *appInfo, args []string) error {
+	var date string
+	var n int
+	var cfgOpts configOpts
+	var srcOpts sourceOpts
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("%s explain describes why a task does or does not appear on a date\n", info.name)
+		fmt.Print("\nUsage:\n")
+		fmt.Printf("  %s explain <id | text> [flags]\n", info.name)
+		fmt.Printf("\nArgs:\n")
+		fmt.Printf("  id\t\t identifier of a task, displayed with the --ids flag\n")
+		fmt.Printf("  text\t\t case-insensitive text contained in the tasks to explain\n")
+		fmt.Printf("\nFlags:\n")
+		fmt.Printf("  -d, --date\t date in YYYY-MM-DD format \t\t\tdefault: today\n")
+		fmt.Printf("  -n\t\t number of occurrences before and after the date \tdefault: 3\n")
+		fmt.Printf("      --profile\t name of the config file profile to use\n")
+		fmt.Printf("      --config\t path to the config file\n")
+	}
+	fs.StringVar(&date, "d", "", "date (YYYY-MM-DD)")
+	fs.StringVar(&date, "date", "", "date (YYYY-MM-DD)")
+	fs.IntVar(&n, "n", 3, "number of occurrences before and after the date")
+	cfgOpts.addFlags(fs)
+	positional, err := parseInterspersed(fs, args[1:])
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("missing identifier or text of task to explain")
+	}
+	if n <= 0 {
+		return fmt.Errorf("invalid non-positive value: -n %d", n)
+	}
+	d, err := parseDate(date)
+	if err != nil {
+		return err
+	}
+	d = fixDate(d)
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	err = srcOpts.load(cfg.Sources)
+	if err != nil {
+		return err
+	}
+	loader := tasks.NewLoader(nil, nil)
+	srcOpts.addTo(loader)
+
+	query := strings.Join(positional, " ")
+	lines, err := findLines(loader, query)
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("no task with identifier or text [%s]", query)
+	}
+
+	log, err := openDoneLog()
+	if err != nil {
+		return err
+	}
+	snoozed, err := openSnoozeStore()
+	if err != nil {
+		return err
+	}
+
+	for i, tsks := range lines {
+		if i > 0 {
+			fmt.Println()
+		}
+		src := tsks[0].Source()
+		fmt.Printf("[%s] %s\n", tsks[0].ID(), tsks[0])
+		fmt.Printf("  source: %s\n", src)
+		fmt.Printf("  line:   %s\n", strings.TrimSpace(src.Raw))
+		for _, tsk := range tsks {
+			if f, ok := tsk.(*sources.Floating); ok {
+				f.SetToday(time.Now())
+				if last, done := log.LastDone(f.ID()); done {
+					f.SetLastDone(last)
+				}
+			}
+			printExplanation(tasks.Explain(tsk, d, n, snoozed))
+		}
+	}
+	return nil
+}
+
+// findLines returns the tasks of the source file line with an identifier or otherwise of the lines whose
+// text contains a query, ignoring case, with the tasks of each line grouped in the order they were found
+func findLines(loader *tasks.Loader, query string) ([][]tasks.Task, error) {
+	tsks, err := loader.Find(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tsks) > 0 {
+		return [][]tasks.Task{tsks}, nil
+	}
+
+	all, err := loader.Tasks()
+	if err != nil {
+		return nil, err
+	}
+	lines := [][]tasks.Task{}
+	index := make(map[string]int)
+	query = strings.ToLower(query)
+	for _, tsk := range all {
+		if !strings.Contains(strings.ToLower(tsk.String()), query) {
+			continue
+		}
+		i, ok := index[tsk.ID()]
+		if !ok {
+			i = len(lines)
+			index[tsk.ID()] = i
+			lines = append(lines, []tasks.Task{})
+		}
+		lines[i] = append(lines[i], tsk)
+	}
+	return lines, nil
+}
+
+func printExplanation(e *tasks.Explanation) {
+	src := e.Task.Source()
+	fmt.Printf("  rule:   %s, %s\n", src.Type, e.Rule)
+	fmt.Printf("  %s: %s\n", e.Date.Format(printTimeFormat), verdict(e))
+
+	if len(e.Occurrences) == 0 {
+		return
+	}
+	fmt.Println("  occurrences:")
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, o := range e.Occurrences {
+		marker := " "
+		if sameDay(o.To, e.Date) || sameDay(o.Date, e.Date) {
+			marker = ">"
+		}
+		notes := []string{}
+		if o.Adjustment != "" {
+			notes = append(notes, o.Adjustment)
+		}
+		if o.Moved() {
+			notes = append(notes, "snoozed to "+o.To.Format(printTimeFormat))
+		}
+		days := daysUntil(e.Date, o.Date)
+		fmt.Fprintf(w, "  %s %s\t%s\t%s\n", marker, o.Date.Format(printTimeFormat), daysAround(days), strings.Join(notes, "; "))
+	}
+	_ = w.Flush()
+	// trim the padding of occurrences without notes
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+}
+
+// verdict describes whether the task of an Explanation appears on its date and why
+func verdict(e *tasks.Explanation) string {
+	if o, ok := e.On(); ok {
+		switch {
+		case o.Moved():
+			return fmt.Sprintf("appears since its occurrence on %s was snoozed to this date", o.Date.Format(printTimeFormat))
+		case o.Adjustment != "":
+			return "appears since " + o.Adjustment
+		}
+		return "appears on the date set by its rule"
+	}
+	if o, ok := e.MovedFrom(); ok {
+		return fmt.Sprintf("does not appear since its occurrence on this date was snoozed to %s", o.To.Format(printTimeFormat))
+	}
+	if d, ok := e.Task.(*sources.Deadline); ok {
+		days := d.DaysFrom(e.Date)
+		due := e.Date.AddDate(0, 0, days)
+		if days > 0 && days <= d.WarningDays() {
+			return fmt.Sprintf("appears counting down to its due date on %s", due.Format(printTimeFormat))
+		}
+		if days < 0 {
+			return fmt.Sprintf("does not appear on this date but is overdue since %s until it is done", due.Format(printTimeFormat))
+		}
+	}
+	if f, ok := e.Task.(*sources.Floating); ok && f.DaysFrom(e.Date) < 0 {
+		due := e.Date.AddDate(0, 0, f.DaysFrom(e.Date))
+		return fmt.Sprintf("does not appear on this date but is overdue since %s until it is done", due.Format(printTimeFormat))
+	}
+	for _, o := range e.Occurrences {
+		if o.Date.After(e.Date) {
+			return fmt.Sprintf("does not appear, next occurring on %s", o.Date.Format(printTimeFormat))
+		}
+	}
+	return "does not appear and does not occur after this date"
+}
+
+// sameDay reports whether two times are on the same calendar date
+func sameDay(a time.Time, b time.Time) bool {
+	return a.Format(inputDateFormat) == b.Format(inputDateFormat)
+}
+
+// daysAround describes the number of days from a date to an occurrence
+func daysAround(days int) string {
+	switch {
+	case days == 0:
+		return "on the date"
+	case days == 1:
+		return "1 day after"
+	case days == -1:
+		return "1 day before"
+	case days < 0:
+		return fmt.Sprintf("%d days before", -days)
+	}
+	return fmt.Sprintf("%d days after", days)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/fileutil"
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+// errNoUpdate is returned by an update function to leave a file unchanged
+var errNoUpdate = errors.New("no update")
+
+// updateFile applies an update to a source file of the specified type while holding a lock on the
+// file and writes it atomically, failing if the file is changed by another process during the update.
+// The file is left unchanged and no error is returned if the update returns errNoUpdate.
+func updateFile(fp string, typ sources.Type, update func(*sources.File) error) error {
+	unlock, err := fileutil.Lock(fp)
+	if err != nil {
+		return err
+	}
+	defer unlock() //nolint
+
+	contents, err := os.ReadFile(filepath.Clean(fp))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	f, err := sources.ReadFile(bytes.NewReader(contents), typ)
+	if err != nil {
+		return fmt.Errorf("%s: %v", fp, err)
+	}
+
+	err = update(f)
+	if errors.Is(err, errNoUpdate) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// the lock is only respected by this program, so check for changes made by any other process
+	current, err := os.ReadFile(filepath.Clean(fp))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if !bytes.Equal(current, contents) {
+		return fmt.Errorf("%s was changed by another process during the update: try again", fp)
+	}
+	return fileutil.WriteFileAtomic(fp, f.Bytes())
+}
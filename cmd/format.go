@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/fileutil"
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks"
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+// runFormat executes the fmt command
+func runFormat(info *appInfo, args []string) error {
+	var check bool
+	var cfgOpts configOpts
+	var srcOpts sourceOpts
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("%s fmt rewrites task source files in canonical form\n", info.name)
+		fmt.Print("\nUsage:\n")
+		fmt.Printf("  %s fmt [flags] [files]\n", info.name)
+		fmt.Printf("\nArgs:\n")
+		fmt.Printf("  files\t\t source files to format \t\t\tdefault: all configured source files\n")
+		fmt.Printf("\nFlags:\n")
+		fmt.Printf("      --check\t list files that are not formatted without changing them\n")
+		fmt.Printf("      --profile\t name of the config file profile to use\n")
+		fmt.Printf("      --config\t path to the config file\n")
+	}
+	fs.BoolVar(&check, "check", false, "list files that are not formatted")
+	cfgOpts.addFlags(fs)
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+	paths := fs.Args()
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	// files passed as arguments can be formatted without any configured sources
+	err = srcOpts.load(cfg.Sources)
+	if err != nil && len(paths) == 0 {
+		return err
+	}
+
+	loader := tasks.NewLoader(nil, nil)
+	srcOpts.addTo(loader)
+	files, err := loader.Files(paths...)
+	if err != nil {
+		return err
+	}
+
+	unformatted := 0
+	for _, typ := range sources.Types {
+		for _, fp := range files[typ] {
+			changed, err := formatFile(fp, typ, check)
+			if err != nil {
+				return err
+			}
+			if changed {
+				unformatted++
+				fmt.Println(fp)
+			}
+		}
+	}
+
+	if check && unformatted > 0 {
+		return fmt.Errorf("%d file(s) not formatted", unformatted)
+	}
+	return nil
+}
+
+// formatFile formats a source file, returning whether its contents changed (or would change if only
+// checking)
+func formatFile(fp string, typ sources.Type, check bool) (bool, error) {
+	contents, err := os.ReadFile(filepath.Clean(fp))
+	if err != nil {
+		return false, err
+	}
+
+	formatted, err := sources.Format(bytes.NewReader(contents), typ)
+	if err != nil {
+		return false, fmt.Errorf("%s: %v", fp, err)
+	}
+	if bytes.Equal(contents, formatted) {
+		return false, nil
+	}
+	if check {
+		return true, nil
+	}
+	return true, fileutil.WriteFileAtomic(fp, formatted)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks"
+)
+
+type lintOpts struct {
+	date   string
+	strict bool
+	paths  []string
+
+	sourceOpts
+}
+
+// runLint executes the lint command
+func runLint(info *appInfo, args []string) error {
+	opts := &lintOpts{}
+	var cfgOpts configOpts
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("%s lint checks task source files for problems without displaying tasks\n", info.name)
+		fmt.Print("\nUsage:\n")
+		fmt.Printf("  %s lint [flags] [files]\n", info.name)
+		fmt.Printf("\nArgs:\n")
+		fmt.Printf("  files\t\t source files to check \t\t\t\tdefault: all configured source files\n")
+		fmt.Printf("\nFlags:\n")
+		fmt.Printf("  -d, --date\t date in YYYY-MM-DD format for past tasks \tdefault: today\n")
+		fmt.Printf("      --strict\t exit with an error for warnings as well as errors\n")
+		fmt.Printf("      --profile\t name of the config file profile to use\n")
+		fmt.Printf("      --config\t path to the config file\n")
+	}
+	fs.StringVar(&opts.date, "d", "", "date for past tasks (YYYY-MM-DD)")
+	fs.StringVar(&opts.date, "date", "", "date for past tasks (YYYY-MM-DD)")
+	fs.BoolVar(&opts.strict, "strict", false, "exit with an error for warnings")
+	cfgOpts.addFlags(fs)
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+	opts.paths = fs.Args()
+
+	date, err := parseDate(opts.date)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	// files passed as arguments can be checked without any configured sources
+	err = opts.sourceOpts.load(cfg.Sources)
+	if err != nil && len(opts.paths) == 0 {
+		return err
+	}
+
+	loader := tasks.NewLoader(nil, nil)
+	opts.sourceOpts.addTo(loader)
+	problems, err := loader.Lint(fixDate(date), opts.paths...)
+	if err != nil {
+		return err
+	}
+
+	numErrors := 0
+	for _, p := range problems {
+		if p.Severity == tasks.SeverityError || opts.strict {
+			numErrors++
+		}
+	}
+
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) == 0 {
+		fmt.Println("no problems")
+	}
+
+	if numErrors > 0 {
+		return fmt.Errorf("lint failed with %d problem(s)", numErrors)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks"
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+type pruneOpts struct {
+	before  time.Time
+	archive string
+	dryRun  bool
+	paths   []string
+
+	sourceOpts
+}
+
+// runPrune executes the prune command
+func runPrune(info *appInfo, args []string) error {
+	opts := &pruneOpts{}
+	var before string
+	var cfgOpts configOpts
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("%s prune removes past single tasks from task source files and archives them\n", info.name)
+		fmt.Print("\nUsage:\n")
+		fmt.Printf("  %s prune [flags] [files]\n", info.name)
+		fmt.Printf("\nArgs:\n")
+		fmt.Printf("  files\t\t source files to prune \t\t\t\tdefault: all configured single and mixed source files\n")
+		fmt.Printf("\nFlags:\n")
+		fmt.Printf("      --before\t remove tasks before date in YYYY-MM-DD format \tdefault: today\n")
+		fmt.Printf("      --archive\t file to which removed tasks are appended \tdefault: <source file>%s\n", tasks.ArchiveSuffix)
+		fmt.Printf("  -n, --dry-run\t display the tasks that would be removed without changing any files\n")
+		fmt.Printf("      --profile\t name of the config file profile to use\n")
+		fmt.Printf("      --config\t path to the config file\n")
+	}
+	fs.StringVar(&before, "before", "", "remove tasks before date (YYYY-MM-DD)")
+	fs.StringVar(&opts.archive, "archive", "", "file to which removed tasks are appended")
+	fs.BoolVar(&opts.dryRun, "n", false, "display tasks that would be removed")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "display tasks that would be removed")
+	cfgOpts.addFlags(fs)
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+	opts.paths = fs.Args()
+
+	beforeDate, err := parseDate(before)
+	if err != nil {
+		return fmt.Errorf("invalid date: --before %s does not match YYYY-MM-DD format", before)
+	}
+	opts.before = fixDate(beforeDate)
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	// files passed as arguments can be pruned without any configured sources
+	err = opts.sourceOpts.load(cfg.Sources)
+	if err != nil && len(opts.paths) == 0 {
+		return err
+	}
+
+	loader := tasks.NewLoader(nil, nil)
+	opts.sourceOpts.addTo(loader)
+	files, err := loader.Files(opts.paths...)
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for _, typ := range []sources.Type{sources.TypeSingle, sources.TypeAuto} {
+		for _, fp := range files[typ] {
+			n, err := pruneFile(fp, typ, opts)
+			if err != nil {
+				return err
+			}
+			total += n
+		}
+	}
+
+	if total == 0 {
+		fmt.Printf("no tasks before %s\n", opts.before.Format(inputDateFormat))
+	}
+	return nil
+}
+
+// pruneFile prunes a source file, returning the number of tasks removed
+func pruneFile(fp string, typ sources.Type, opts *pruneOpts) (int, error) {
+	// a missing file has nothing to prune but is reported rather than created
+	if _, err := os.Stat(filepath.Clean(fp)); err != nil {
+		return 0, err
+	}
+
+	archive := opts.archive
+	if archive == "" {
+		archive = fp + tasks.ArchiveSuffix
+	}
+
+	var pruned []string
+	err := updateFile(fp, typ, func(f *sources.File) error {
+		pruned = sources.Prune(f, opts.before)
+		if len(pruned) == 0 || opts.dryRun {
+			return errNoUpdate
+		}
+		// archive the tasks before removing them so that they are never lost
+		return appendArchive(archive, fp, opts.before, pruned)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(pruned) == 0 {
+		return 0, nil
+	}
+
+	if opts.dryRun {
+		fmt.Printf("%s: would prune %d task(s)\n", fp, len(pruned))
+		for _, line := range pruned {
+			fmt.Printf("\t%s\n", line)
+		}
+		return len(pruned), nil
+	}
+	fmt.Printf("%s: pruned %d task(s) to %s\n", fp, len(pruned), archive)
+	return len(pruned), nil
+}
+
+// appendArchive appends pruned tasks to an archive file under a comment recording their source
+func appendArchive(archive string, source string, before time.Time, pruned []string) error {
+	abs, err := filepath.Abs(source)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# pruned from %s on %s (tasks before %s)\n", abs, time.Now().Format(inputDateFormat), before.Format(inputDateFormat))
+	for _, line := range pruned {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	f, err := os.OpenFile(filepath.Clean(archive), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(b.String())
+	if err != nil {
+		f.Close() //nolint
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks"
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+// runRemove executes the rm command
+func runRemove(info *appInfo, args []string) error {
+	var cfgOpts configOpts
+	var srcOpts sourceOpts
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("%s rm removes tasks from task source files\n", info.name)
+		fmt.Print("\nUsage:\n")
+		fmt.Printf("  %s rm [flags] <id>...\n", info.name)
+		fmt.Printf("\nArgs:\n")
+		fmt.Printf("  id\t\t identifier of a task, displayed with the --ids flag\n")
+		fmt.Printf("\nFlags:\n")
+		fmt.Printf("      --profile\t name of the config file profile to use\n")
+		fmt.Printf("      --config\t path to the config file\n")
+	}
+	cfgOpts.addFlags(fs)
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("missing identifier of task to remove")
+	}
+
+	files, err := configuredFiles(cfgOpts, srcOpts)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range fs.Args() {
+		var removed string
+		src, err := updateTask(files, id, func(l *sources.FileLine) error {
+			removed = l.Text
+			l.Remove()
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("removed [%s] from %s\n", removed, src)
+	}
+	return nil
+}
+
+// configuredFiles returns the configured source files of each type
+func configuredFiles(cfgOpts configOpts, srcOpts sourceOpts) (map[sources.Type][]string, error) {
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return nil, err
+	}
+	err = srcOpts.load(cfg.Sources)
+	if err != nil {
+		return nil, err
+	}
+	loader := tasks.NewLoader(nil, nil)
+	srcOpts.addTo(loader)
+	return loader.Files()
+}
+
+// updateTask applies an update to the line of the task with an identifier and returns where the line was
+// found before the update. The line is located without locking any file, so that only the file containing
+// it is locked and updated.
+func updateTask(files map[sources.Type][]string, id string, update func(*sources.FileLine) error) (sources.Source, error) {
+	notFound := fmt.Errorf("no task with identifier [%s]: the task may have been changed since its identifier was displayed", id)
+
+	fp, typ, ok := findTaskFile(files, id)
+	if !ok {
+		return sources.Source{}, notFound
+	}
+	var src *sources.Source
+	err := updateFile(fp, typ, func(f *sources.File) error {
+		l := f.Find(fp, id)
+		if l == nil {
+			return errNoUpdate
+		}
+		src = &sources.Source{Type: l.Type, Path: fp, Line: l.Number, Raw: l.Text}
+		return update(l)
+	})
+	if err != nil {
+		return sources.Source{}, err
+	}
+	if src == nil {
+		return sources.Source{}, notFound
+	}
+	return *src, nil
+}
+
+// findTaskFile returns the source file containing the line of the task with an identifier and the type of
+// the file, skipping files that cannot be read
+func findTaskFile(files map[sources.Type][]string, id string) (string, sources.Type, bool) {
+	for _, typ := range sources.Types {
+		for _, fp := range files[typ] {
+			contents, err := os.ReadFile(filepath.Clean(fp))
+			if err != nil {
+				continue
+			}
+			f, err := sources.ReadFile(bytes.NewReader(contents), typ)
+			if err != nil {
+				continue
+			}
+			if f.Find(fp, id) != nil {
+				return fp, typ, true
+			}
+		}
+	}
+	return "", "", false
+}
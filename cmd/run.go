@@ -1,18 +1,24 @@
 package cmd
 
 import (
-	"flag"
 	"fmt"
-	"sort"
-	"strings"
+	"os"
 	"time"
 
+	"github.com/dkaslovsky/calendar-tasks/pkg/completions"
+	"github.com/dkaslovsky/calendar-tasks/pkg/snooze"
 	"github.com/dkaslovsky/calendar-tasks/pkg/tasks"
 )
 
 // format for displaying dates
 const printTimeFormat = "[Mon] Jan 2 2006"
 
+// mark displayed before tasks marked as done
+const doneMark = "✓"
+
+// priority of tasks displayed in their own color
+const highPriority = 1
+
 // Run excutes the CLI
 func Run(name string, version string, argsIn []string) error {
 	info := &appInfo{
@@ -20,10 +26,14 @@ func Run(name string, version string, argsIn []string) error {
 		version: version,
 	}
 
-	flag.Usage = info.setUsage()
+	if len(argsIn) > 1 {
+		if command, ok := commands[argsIn[1]]; ok {
+			return command(info, argsIn[1:])
+		}
+	}
 
 	opts := &cliOpts{}
-	err := parseArgs(argsIn, opts)
+	err := parseArgs(info, argsIn, opts)
 	if err != nil {
 		return err
 	}
@@ -33,9 +43,30 @@ func Run(name string, version string, argsIn []string) error {
 		return nil
 	}
 
+	if opts.noColor {
+		disableColor()
+	}
+
 	return run(opts)
 }
 
+// commands maps the name of each command to the function that executes it
+var commands = map[string]func(info *appInfo, args []string) error{
+	"add":     runAdd,
+	"config":  runConfig,
+	"done":    runDone,
+	"edit":    runEdit,
+	"explain": runExplain,
+	"fmt":     runFormat,
+	"lint":    runLint,
+	"prune":   runPrune,
+	"rm":      runRemove,
+	"search":  runSearch,
+	"snooze":  runSnooze,
+	"stats":   runStats,
+	"when":    runWhen,
+}
+
 func run(opts *cliOpts) error {
 	runDates := getRunDates(opts)
 
@@ -45,18 +76,61 @@ func run(opts *cliOpts) error {
 	loader := tasks.NewLoader(taskChan, doneChan)
 	processor := tasks.NewProcessor(runDates.start, runDates.numDays, taskChan, doneChan)
 
-	loader.AddWeeklySource(opts.weeklySources...)
-	loader.AddMonthlySource(opts.monthlySources...)
-	loader.AddAnnualSource(opts.annualSources...)
-	loader.AddSingleSource(opts.singleSources...)
+	loader.SetKeepGoing(opts.keepGoing)
+	opts.sourceOpts.addTo(loader)
 
-	err := processTasks(loader, processor)
+	doneLog, err := openDoneLog()
 	if err != nil {
 		return err
 	}
+	snoozed, err := openSnoozeStore()
+	if err != nil {
+		return err
+	}
+	processor.SetOverdue(runDates.today, opts.overdue, doneLog)
+	processor.SetOverrides(snoozed)
+	processor.SetFilter(&tasks.Filter{
+		Tags:        opts.tags,
+		ExcludeTags: opts.excludeTags,
+		Contexts:    opts.contexts,
+		MinPriority: opts.minPriority,
+		Labels:      opts.labels,
+	})
 
-	printTasks(processor, runDates)
-	return nil
+	err = processTasks(loader, processor)
+	if err != nil {
+		return err
+	}
+
+	if opts.listTags {
+		printTags(processor)
+	} else {
+		printTasks(processor, runDates, opts, doneLog, snoozed)
+	}
+
+	return reportErrors(loader.Errors())
+}
+
+// reportErrors prints errors collected while loading tasks and returns an error summarizing them
+func reportErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	fmt.Fprintln(os.Stderr)
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	return fmt.Errorf("failed to load %d task(s) from source files", len(errs))
+}
+
+// addTo adds the source files to a Loader
+func (opts *sourceOpts) addTo(loader *tasks.Loader) {
+	loader.SetFileFilter(opts.include, opts.exclude)
+	loader.AddWeeklySource(opts.weeklySources...)
+	loader.AddMonthlySource(opts.monthlySources...)
+	loader.AddAnnualSource(opts.annualSources...)
+	loader.AddSingleSource(opts.singleSources...)
+	loader.AddMixedSource(opts.mixedSources...)
 }
 
 // processTasks starts the processor and loader and waits on the processor before returning
@@ -68,24 +142,33 @@ func processTasks(loader *tasks.Loader, processor *tasks.Processor) error {
 	return loader.Start()
 }
 
-func printTasks(processor *tasks.Processor, dates *runDates) {
+func printTasks(processor *tasks.Processor, dates *runDates, opts *cliOpts, doneLog *completions.Log, snoozed *snooze.Store) {
 	numTasks := 0
 
+	overdue := processor.GetOverdue()
+	if len(overdue) > 0 {
+		colorPrint(colorOverdue, "Overdue", "\n")
+	}
+	for _, o := range overdue {
+		late := fmt.Sprintf(" (%s, %s)", o.Date.Format(printTimeFormat), daysLate(o.DaysLate))
+		colorPrint(colorOverdue, taskPrefix(o.Task, opts, false), o.Task.String(), late, taskSuffix(o.Task, opts), "\n")
+		numTasks++
+	}
+
 	for day := 0; day <= dates.numDays; day++ {
 		tsks, ok := processor.GetTasks(day)
 		if !ok {
 			continue
 		}
 
-		// sort for consistent ordering
-		sort.Slice(tsks, func(i, j int) bool {
-			return strings.ToLower(tsks[i].String()) < strings.ToLower(tsks[j].String())
-		})
+		// sort by priority and then text for consistent ordering
+		tasks.SortTasks(tsks)
 
 		// format printing
+		curDay := dates.start.AddDate(0, 0, day)
 		var clr color
 		var curDayStr string
-		switch curDay := dates.start.AddDate(0, 0, day); {
+		switch {
 		case curDay == dates.today:
 			curDayStr = curDay.Format(printTimeFormat) + " (today)"
 			clr = colorToday
@@ -98,9 +181,23 @@ func printTasks(processor *tasks.Processor, dates *runDates) {
 			clr = colorPast
 		}
 
-		colorPrint(clr, curDayStr, "\n")
+		printed := false
 		for _, tsk := range tsks {
-			colorPrint(clr, "\t-", tsk, "\n")
+			occurrence := displayedOccurrence(tsk, curDay, snoozed)
+			done := doneLog.Done(tsk.ID(), occurrence.Date)
+			if done && opts.hideDone {
+				continue
+			}
+			if !printed {
+				colorPrint(clr, curDayStr, "\n")
+				printed = true
+			}
+
+			suffix := ""
+			if !occurrence.Date.Equal(occurrence.To) {
+				suffix = fmt.Sprintf(" (snoozed from %s)", occurrence.Date.Format(printTimeFormat))
+			}
+			colorPrint(taskColor(tsk, clr), taskPrefix(tsk, opts, done), tsk.String(), suffix, taskSuffix(tsk, opts), "\n")
 			numTasks++
 		}
 	}
@@ -110,6 +207,81 @@ func printTasks(processor *tasks.Processor, dates *runDates) {
 	}
 }
 
+func printTags(processor *tasks.Processor) {
+	tags := processor.GetTags()
+	if len(tags) == 0 {
+		fmt.Println("no tags")
+		return
+	}
+	for _, tc := range tags {
+		fmt.Printf("%s (%d)\n", tc.Name, tc.Count)
+	}
+}
+
+// displayedOccurrence returns the occurrence of a task displayed on a date, with the original date of the
+// occurrence if it was snoozed, which for a reminder is the occurrence of which it reminds and for a
+// countdown is the occurrence on which its task is due
+func displayedOccurrence(tsk tasks.Task, date time.Time, snoozed *snooze.Store) tasks.Override {
+	switch w := tsk.(type) {
+	case *tasks.Reminder:
+		return displayedOccurrence(w.Task, w.Occurrence(date), snoozed)
+	case *tasks.Countdown:
+		return displayedOccurrence(w.Task, w.Occurrence(date), snoozed)
+	}
+	if o, ok := tasks.LastOccurrence(tsk, date, 0, snoozed); ok {
+		return o
+	}
+	return tasks.Override{Date: date, To: date}
+}
+
+// taskPrefix returns the text printed before a task
+func taskPrefix(tsk tasks.Task, opts *cliOpts, done bool) string {
+	prefix := "\t- "
+	if opts.showIDs {
+		prefix += "[" + tsk.ID() + "] "
+	}
+	if done {
+		prefix += doneMark + " "
+	}
+	if label := tasks.LabelOf(tsk); label != "" {
+		prefix += "[" + label + "] "
+	}
+	return prefix
+}
+
+// taskSuffix returns the text printed after a task
+func taskSuffix(tsk tasks.Task, opts *cliOpts) string {
+	if opts.showSources {
+		return " (" + tsk.Source().String() + ")"
+	}
+	return ""
+}
+
+// taskColor returns the color in which a task is printed, which is the color for reminders or high priority
+// tasks, the color set in the header of the task's source file, or otherwise the color of its day
+func taskColor(tsk tasks.Task, dayColor color) color {
+	if _, ok := tsk.(*tasks.Reminder); ok {
+		return colorReminder
+	}
+	if tasks.PriorityOf(tsk) == highPriority {
+		return colorPriority
+	}
+	if c, ok := tasks.Unwrap(tsk).(interface{ Color() string }); ok {
+		if clr, ok := fileColors[c.Color()]; ok {
+			return clr
+		}
+	}
+	return dayColor
+}
+
+// daysLate describes the number of days by which a task is overdue
+func daysLate(days int) string {
+	if days == 1 {
+		return "1 day late"
+	}
+	return fmt.Sprintf("%d days late", days)
+}
+
 type runDates struct {
 	today   time.Time
 	start   time.Time
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks"
+)
+
+type searchOpts struct {
+	limit   int
+	date    string
+	showIDs bool
+}
+
+// runSearch executes the search command
+func runSearch(info *appInfo, args []string) error {
+	opts := &searchOpts{}
+	var cfgOpts configOpts
+	var srcOpts sourceOpts
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("%s search finds the tasks whose text fuzzily matches a query, from the best match\n", info.name)
+		fmt.Print("\nUsage:\n")
+		fmt.Printf("  %s search <query> [flags]\n", info.name)
+		fmt.Printf("\nArgs:\n")
+		fmt.Printf("  query\t\t words whose characters appear in order in the text of each task, ignoring case\n")
+		fmt.Printf("\nFlags:\n")
+		fmt.Printf("  -n, --limit\t maximum number of results, or 0 for all \t\tdefault: 10\n")
+		fmt.Printf("  -d, --date\t date in YYYY-MM-DD format from which to find next occurrences \tdefault: today\n")
+		fmt.Printf("      --ids\t display the identifier of each task\n")
+		fmt.Printf("      --profile\t name of the config file profile to use\n")
+		fmt.Printf("      --config\t path to the config file\n")
+	}
+	fs.IntVar(&opts.limit, "n", 10, "maximum number of results")
+	fs.IntVar(&opts.limit, "limit", 10, "maximum number of results")
+	fs.StringVar(&opts.date, "d", "", "date from which to find next occurrences (YYYY-MM-DD)")
+	fs.StringVar(&opts.date, "date", "", "date from which to find next occurrences (YYYY-MM-DD)")
+	fs.BoolVar(&opts.showIDs, "ids", false, "display task identifiers")
+	cfgOpts.addFlags(fs)
+	positional, err := parseInterspersed(fs, args[1:])
+	if err != nil {
+		return err
+	}
+	query := strings.Join(positional, " ")
+	if strings.TrimSpace(query) == "" {
+		return errors.New("missing query of tasks to search")
+	}
+	if opts.limit < 0 {
+		return fmt.Errorf("invalid negative value: --limit %d", opts.limit)
+	}
+	date, err := parseDate(opts.date)
+	if err != nil {
+		return err
+	}
+	date = fixDate(date)
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	err = srcOpts.load(cfg.Sources)
+	if err != nil {
+		return err
+	}
+	loader := tasks.NewLoader(nil, nil)
+	srcOpts.addTo(loader)
+
+	all, err := loader.Tasks()
+	if err != nil {
+		return err
+	}
+	results := tasks.Search(all, query)
+	if opts.limit > 0 && len(results) > opts.limit {
+		results = results[:opts.limit]
+	}
+
+	log, err := openDoneLog()
+	if err != nil {
+		return err
+	}
+	snoozed, err := openSnoozeStore()
+	if err != nil {
+		return err
+	}
+	// only the next occurrences of the displayed results are found since searching for them is slower
+	// than matching the query
+	upcomings := []*upcoming{}
+	for _, r := range results {
+		upcomings = append(upcomings, newUpcoming(r.Tasks, date, 1, log, snoozed))
+	}
+
+	printSearch(upcomings, date, query, opts)
+	return nil
+}
+
+// canonicalDates returns the dates of the tasks of a source file line in canonical form
+func canonicalDates(tsks []tasks.Task) string {
+	dates := []string{}
+	for _, tsk := range tsks {
+		if d, ok := tsk.(interface{ Date() string }); ok {
+			dates = append(dates, d.Date())
+		}
+	}
+	return strings.Join(dates, "/")
+}
+
+func printSearch(upcomings []*upcoming, date time.Time, query string, opts *searchOpts) {
+	if len(upcomings) == 0 {
+		fmt.Printf("no tasks matching [%s]\n", query)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK\tTYPE\tRULE\tSOURCE\tNEXT")
+	for _, u := range upcomings {
+		name := u.task.String()
+		if opts.showIDs {
+			name = "[" + u.task.ID() + "] " + name
+		}
+		next := "none"
+		if len(u.occurrences) > 0 {
+			next = u.describe(u.occurrences[0], date)
+		}
+		src := u.task.Source()
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, src.Type, canonicalDates(u.line), src, next)
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/snooze"
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks"
+)
+
+// snoozeBy matches a --by duration, such as 2d or 1w
+var snoozeBy = regexp.MustCompile(`^(\d+)([dw])$`)
+
+type snoozeOpts struct {
+	to    string
+	by    string
+	date  string
+	list  bool
+	clear bool
+}
+
+// runSnooze executes the snooze command
+func runSnooze(info *appInfo, args []string) error {
+	opts := &snoozeOpts{}
+	var cfgOpts configOpts
+	var srcOpts sourceOpts
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("%s snooze moves an occurrence of a task to another date without changing its source file\n", info.name)
+		fmt.Print("\nUsage:\n")
+		fmt.Printf("  %s snooze <id> --to <date> | --by <duration> [flags]\n", info.name)
+		fmt.Printf("  %s snooze --list\n", info.name)
+		fmt.Printf("  %s snooze --clear <id> [flags]\n", info.name)
+		fmt.Printf("\nArgs:\n")
+		fmt.Printf("  id\t\t identifier of a task, displayed with the --ids flag\n")
+		fmt.Printf("\nFlags:\n")
+		fmt.Printf("      --to\t date in YYYY-MM-DD format to which the occurrence is moved\n")
+		fmt.Printf("      --by\t number of days (e.g., 2d) or weeks (e.g., 1w) by which the occurrence is moved\n")
+		fmt.Printf("  -d, --date\t original date of the occurrence in YYYY-MM-DD format \tdefault: next occurrence\n")
+		fmt.Printf("      --list\t display the snoozed occurrences\n")
+		fmt.Printf("      --clear\t return snoozed occurrences to their original dates \tdefault: all occurrences of the task\n")
+		fmt.Printf("      --profile\t name of the config file profile to use\n")
+		fmt.Printf("      --config\t path to the config file\n")
+	}
+	fs.StringVar(&opts.to, "to", "", "date to which the occurrence is moved (YYYY-MM-DD)")
+	fs.StringVar(&opts.by, "by", "", "duration by which the occurrence is moved")
+	fs.StringVar(&opts.date, "d", "", "original date of the occurrence (YYYY-MM-DD)")
+	fs.StringVar(&opts.date, "date", "", "original date of the occurrence (YYYY-MM-DD)")
+	fs.BoolVar(&opts.list, "list", false, "display the snoozed occurrences")
+	fs.BoolVar(&opts.clear, "clear", false, "return snoozed occurrences to their original dates")
+	cfgOpts.addFlags(fs)
+	ids, err := parseInterspersed(fs, args[1:])
+	if err != nil {
+		return err
+	}
+
+	store, err := openSnoozeStore()
+	if err != nil {
+		return err
+	}
+	if opts.list {
+		listSnoozed(store)
+		return nil
+	}
+
+	if len(ids) != 1 {
+		return errors.New("snooze requires the identifier of exactly one task")
+	}
+	id := ids[0]
+	if !opts.clear && (opts.to == "") == (opts.by == "") {
+		return errors.New("snooze requires exactly one of --to or --by")
+	}
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	err = srcOpts.load(cfg.Sources)
+	if err != nil {
+		return err
+	}
+	loader := tasks.NewLoader(nil, nil)
+	srcOpts.addTo(loader)
+
+	tsks, err := loader.Find(id)
+	if err != nil {
+		return err
+	}
+	if len(tsks) == 0 {
+		return fmt.Errorf("no task with identifier [%s]: the task may have been changed since its identifier was displayed", id)
+	}
+
+	if opts.clear {
+		return clearSnoozed(store, id, tsks[0].String(), opts.date)
+	}
+
+	date, err := snoozeOccurrence(tsks, opts.date)
+	if err != nil {
+		return err
+	}
+	to, err := snoozeTarget(store, id, date, opts)
+	if err != nil {
+		return err
+	}
+
+	err = store.Snooze(id, date, to, tsks[0].String())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("snoozed [%s] from %s to %s\n", tsks[0], date.Format(printTimeFormat), to.Format(printTimeFormat))
+	return nil
+}
+
+// snoozeOccurrence returns the original date of the occurrence of the tasks of a line to snooze, which
+// is the date passed with --date or otherwise the next occurrence on or after today
+func snoozeOccurrence(tsks []tasks.Task, date string) (time.Time, error) {
+	if date != "" {
+		d, err := parseDate(date)
+		if err != nil {
+			return time.Time{}, err
+		}
+		d = fixDate(d)
+		for _, tsk := range tsks {
+			if tsk.DaysFrom(d) == 0 {
+				return d, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("task [%s] does not occur on %s", tsks[0], d.Format(printTimeFormat))
+	}
+
+	today := fixDate(time.Now())
+	next := -1
+	for _, tsk := range tsks {
+		if days := tsk.DaysFrom(today); days >= 0 && (next < 0 || days < next) {
+			next = days
+		}
+	}
+	if next < 0 {
+		return time.Time{}, fmt.Errorf("task [%s] has no upcoming occurrence to snooze: use --date to specify one", tsks[0])
+	}
+	return today.AddDate(0, 0, next), nil
+}
+
+// snoozeTarget returns the date to which an occurrence is moved, with --by relative to the date on
+// which the occurrence is currently displayed
+func snoozeTarget(store *snooze.Store, id string, date time.Time, opts *snoozeOpts) (time.Time, error) {
+	if opts.to != "" {
+		to, err := time.Parse(inputDateFormat, opts.to)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date: --to %s does not match YYYY-MM-DD format", opts.to)
+		}
+		return fixDate(to), nil
+	}
+
+	match := snoozeBy.FindStringSubmatch(opts.by)
+	if match == nil {
+		return time.Time{}, fmt.Errorf("invalid duration: --by %s must be a number of days (e.g., 2d) or weeks (e.g., 1w)", opts.by)
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid duration: --by %s: %v", opts.by, err)
+	}
+	if match[2] == "w" {
+		n *= 7
+	}
+
+	current := date
+	for _, o := range store.Overrides(id) {
+		if o.Date.Format(inputDateFormat) == date.Format(inputDateFormat) {
+			current = o.To
+		}
+	}
+	return fixDate(current).AddDate(0, 0, n), nil
+}
+
+// clearSnoozed returns the snoozed occurrences of a task to their original dates
+func clearSnoozed(store *snooze.Store, id string, text string, date string) error {
+	cleared := 0
+	for _, o := range store.Overrides(id) {
+		if date != "" && o.Date.Format(inputDateFormat) != date {
+			continue
+		}
+		err := store.Clear(id, o.Date, text)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("returned [%s] from %s to %s\n", text, o.To.Format(printTimeFormat), o.Date.Format(printTimeFormat))
+		cleared++
+	}
+	if cleared == 0 {
+		return fmt.Errorf("task [%s] has no snoozed occurrences to clear", text)
+	}
+	return nil
+}
+
+// listSnoozed displays the snoozed occurrences of tasks
+func listSnoozed(store *snooze.Store) {
+	records := store.List()
+	if len(records) == 0 {
+		fmt.Println("no snoozed tasks")
+		return
+	}
+	for _, r := range records {
+		fmt.Printf("[%s] %s: %s -> %s\n", r.ID, r.Text, displayDate(r.Date), displayDate(r.To))
+	}
+}
+
+// displayDate formats a YYYY-MM-DD date for display
+func displayDate(date string) string {
+	d, err := time.Parse(inputDateFormat, date)
+	if err != nil {
+		return date
+	}
+	return d.Format(printTimeFormat)
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks"
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+// characters of the sparkline of completed and missed occurrences
+const (
+	sparkDone   = "█"
+	sparkMissed = "▁"
+)
+
+type statsOpts struct {
+	last    int
+	date    string
+	showIDs bool
+}
+
+// runStats executes the stats command
+func runStats(info *appInfo, args []string) error {
+	opts := &statsOpts{}
+	var cfgOpts configOpts
+	var srcOpts sourceOpts
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("%s stats displays the completion streaks and rates of weekly and monthly tasks\n", info.name)
+		fmt.Print("\nUsage:\n")
+		fmt.Printf("  %s stats [flags] [id...]\n", info.name)
+		fmt.Printf("\nArgs:\n")
+		fmt.Printf("  id\t\t identifier of a task, displayed with the --ids flag \tdefault: all weekly and monthly tasks\n")
+		fmt.Printf("\nFlags:\n")
+		fmt.Printf("  -n, --last\t number of most recent occurrences of each task \tdefault: 10\n")
+		fmt.Printf("  -d, --date\t date in YYYY-MM-DD format of the most recent occurrences \tdefault: today\n")
+		fmt.Printf("      --ids\t display the identifier of each task\n")
+		fmt.Printf("      --profile\t name of the config file profile to use\n")
+		fmt.Printf("      --config\t path to the config file\n")
+	}
+	fs.IntVar(&opts.last, "n", 10, "number of most recent occurrences")
+	fs.IntVar(&opts.last, "last", 10, "number of most recent occurrences")
+	fs.StringVar(&opts.date, "d", "", "date of the most recent occurrences (YYYY-MM-DD)")
+	fs.StringVar(&opts.date, "date", "", "date of the most recent occurrences (YYYY-MM-DD)")
+	fs.BoolVar(&opts.showIDs, "ids", false, "display task identifiers")
+	cfgOpts.addFlags(fs)
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+
+	if opts.last <= 0 {
+		return fmt.Errorf("invalid non-positive value: --last %d", opts.last)
+	}
+	date, err := parseDate(opts.date)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	err = srcOpts.load(cfg.Sources)
+	if err != nil {
+		return err
+	}
+	loader := tasks.NewLoader(nil, nil)
+	srcOpts.addTo(loader)
+
+	all, err := loader.Tasks()
+	if err != nil {
+		return err
+	}
+	lines := recurringLines(all, fs.Args())
+	for _, id := range fs.Args() {
+		if _, ok := lines[id]; !ok {
+			return fmt.Errorf("no weekly or monthly task with identifier [%s]", id)
+		}
+	}
+
+	log, err := openDoneLog()
+	if err != nil {
+		return err
+	}
+
+	stats := []*tasks.Stats{}
+	for _, tsks := range lines {
+		stats = append(stats, tasks.NewStats(tsks, fixDate(date), opts.last, log))
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return strings.ToLower(stats[i].Task.String()) < strings.ToLower(stats[j].Task.String())
+	})
+
+	printStats(stats, opts)
+	return nil
+}
+
+// recurringLines groups the weekly and monthly tasks by the source file line from which they were loaded,
+// keeping only the lines with the specified identifiers if any are specified
+func recurringLines(all []tasks.Task, ids []string) map[string][]tasks.Task {
+	keep := make(map[string]bool)
+	for _, id := range ids {
+		keep[id] = true
+	}
+
+	lines := make(map[string][]tasks.Task)
+	for _, tsk := range all {
+		switch tsk.(type) {
+		case *sources.Weekly, *sources.Monthly:
+		default:
+			continue
+		}
+		if len(keep) > 0 && !keep[tsk.ID()] {
+			continue
+		}
+		lines[tsk.ID()] = append(lines[tsk.ID()], tsk)
+	}
+	return lines
+}
+
+func printStats(stats []*tasks.Stats, opts *statsOpts) {
+	if len(stats) == 0 {
+		fmt.Println("no weekly or monthly tasks")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "TASK\tSTREAK\tLONGEST\tRATE\tLAST %d\n", opts.last)
+	for _, s := range stats {
+		name := s.Task.String()
+		if opts.showIDs {
+			name = "[" + s.Task.ID() + "] " + name
+		}
+		rate := fmt.Sprintf("%.0f%% (%d/%d)", 100*s.Rate(), s.Completed(), len(s.History))
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", name, s.CurrentStreak, s.LongestStreak, rate, sparkline(s.History))
+	}
+	_ = w.Flush()
+}
+
+// sparkline draws the completed and missed occurrences of a task from the oldest to the newest
+func sparkline(history []bool) string {
+	var b strings.Builder
+	for _, done := range history {
+		if done {
+			b.WriteString(sparkDone)
+		} else {
+			b.WriteString(sparkMissed)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks"
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+type whenOpts struct {
+	next    int
+	date    string
+	showIDs bool
+}
+
+// upcoming holds the next occurrences of the tasks of a source file line
+type upcoming struct {
+	// task is the first of the tasks of the line, which share its text and identifier
+	task        tasks.Task
+	line        []tasks.Task
+	occurrences []tasks.Override
+	// late is the number of days that a floating task is overdue
+	late int
+}
+
+// runWhen executes the when command
+func runWhen(info *appInfo, args []string) error {
+	opts := &whenOpts{}
+	var cfgOpts configOpts
+	var srcOpts sourceOpts
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("%s when lists the next occurrences of the tasks matching a regular expression\n", info.name)
+		fmt.Print("\nUsage:\n")
+		fmt.Printf("  %s when <regex> [flags]\n", info.name)
+		fmt.Printf("\nArgs:\n")
+		fmt.Printf("  regex\t\t regular expression matched against the text of each task, ignoring case\n")
+		fmt.Printf("\nFlags:\n")
+		fmt.Printf("  -n, --next\t number of next occurrences of each task \t\tdefault: 3\n")
+		fmt.Printf("  -d, --date\t date in YYYY-MM-DD format from which to list occurrences \tdefault: today\n")
+		fmt.Printf("      --ids\t display the identifier of each task\n")
+		fmt.Printf("      --profile\t name of the config file profile to use\n")
+		fmt.Printf("      --config\t path to the config file\n")
+	}
+	fs.IntVar(&opts.next, "n", 3, "number of next occurrences")
+	fs.IntVar(&opts.next, "next", 3, "number of next occurrences")
+	fs.StringVar(&opts.date, "d", "", "date from which to list occurrences (YYYY-MM-DD)")
+	fs.StringVar(&opts.date, "date", "", "date from which to list occurrences (YYYY-MM-DD)")
+	fs.BoolVar(&opts.showIDs, "ids", false, "display task identifiers")
+	cfgOpts.addFlags(fs)
+	positional, err := parseInterspersed(fs, args[1:])
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("missing regular expression of tasks to list")
+	}
+	if opts.next <= 0 {
+		return fmt.Errorf("invalid non-positive value: --next %d", opts.next)
+	}
+	pattern := strings.Join(positional, " ")
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return fmt.Errorf("invalid regular expression [%s]: %v", pattern, err)
+	}
+	date, err := parseDate(opts.date)
+	if err != nil {
+		return err
+	}
+	date = fixDate(date)
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	err = srcOpts.load(cfg.Sources)
+	if err != nil {
+		return err
+	}
+	loader := tasks.NewLoader(nil, nil)
+	srcOpts.addTo(loader)
+
+	all, err := loader.Tasks()
+	if err != nil {
+		return err
+	}
+	log, err := openDoneLog()
+	if err != nil {
+		return err
+	}
+	snoozed, err := openSnoozeStore()
+	if err != nil {
+		return err
+	}
+
+	lines := make(map[string][]tasks.Task)
+	for _, tsk := range all {
+		if re.MatchString(tsk.String()) {
+			lines[tsk.ID()] = append(lines[tsk.ID()], tsk)
+		}
+	}
+
+	upcomings := []*upcoming{}
+	for _, tsks := range lines {
+		if u := newUpcoming(tsks, date, opts.next, log, snoozed); len(u.occurrences) > 0 {
+			upcomings = append(upcomings, u)
+		}
+	}
+	sort.Slice(upcomings, func(i, j int) bool {
+		a, b := upcomings[i].occurrences[0].To, upcomings[j].occurrences[0].To
+		if !sameDay(a, b) {
+			return a.Before(b)
+		}
+		return strings.ToLower(upcomings[i].task.String()) < strings.ToLower(upcomings[j].task.String())
+	})
+
+	printUpcoming(upcomings, date, pattern, opts)
+	return nil
+}
+
+// newUpcoming finds the next n occurrences on or after a date of the tasks of a source file line or, for
+// an overdue floating or deadline task, the date on which it was due
+func newUpcoming(tsks []tasks.Task, date time.Time, n int, store tasks.CompletionStore, overrides tasks.OverrideStore) *upcoming {
+	u := &upcoming{task: tsks[0], line: tsks}
+	if f, ok := tsks[0].(*sources.Floating); ok {
+		f.SetToday(date)
+		if last, done := store.LastDone(f.ID()); done {
+			f.SetLastDone(last)
+		}
+		if days := f.DaysFrom(date); days < 0 {
+			due := date.AddDate(0, 0, days)
+			u.occurrences = []tasks.Override{{Date: due, To: due}}
+			u.late = -days
+			return u
+		}
+	}
+	if d, ok := tsks[0].(*sources.Deadline); ok {
+		days := d.DaysFrom(date)
+		if due := date.AddDate(0, 0, days); days < 0 && !store.Done(d.ID(), due) {
+			u.occurrences = []tasks.Override{{Date: due, To: due}}
+			u.late = -days
+			return u
+		}
+	}
+	u.occurrences = tasks.NextOccurrences(tsks, date, n, overrides)
+	return u
+}
+
+// describe describes an occurrence relative to a date
+func (u *upcoming) describe(o tasks.Override, date time.Time) string {
+	when := daysFromNow(daysUntil(date, o.To))
+	if u.late > 0 {
+		when = daysLate(u.late)
+	}
+	if !sameDay(o.Date, o.To) {
+		when += ", snoozed from " + o.Date.Format(printTimeFormat)
+	}
+	return fmt.Sprintf("%s (%s)", o.To.Format(printTimeFormat), when)
+}
+
+func printUpcoming(upcomings []*upcoming, date time.Time, pattern string, opts *whenOpts) {
+	if len(upcomings) == 0 {
+		fmt.Printf("no upcoming occurrences of tasks matching [%s]\n", pattern)
+		return
+	}
+
+	for _, u := range upcomings {
+		prefix := ""
+		if opts.showIDs {
+			prefix += "[" + u.task.ID() + "] "
+		}
+		if label := tasks.LabelOf(u.task); label != "" {
+			prefix += "[" + label + "] "
+		}
+		fmt.Println(prefix + u.task.String())
+		for _, o := range u.occurrences {
+			fmt.Printf("\t- %s\n", u.describe(o, date))
+		}
+	}
+}
+
+// daysUntil returns the number of calendar days from one date to another, regardless of their locations
+func daysUntil(from time.Time, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
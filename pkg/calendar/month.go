@@ -37,3 +37,11 @@ func DaysInMonth(t time.Time) int {
 	monthEndDate := time.Date(year, month, 1, 0, 0, 0, 0, t.Location()).AddDate(0, 1, -1)
 	return monthEndDate.Day()
 }
+
+// IsValidDate reports whether a day exists in the month of the year (e.g., February 30 does not)
+func IsValidDate(year int, month time.Month, day int) bool {
+	if day < 1 {
+		return false
+	}
+	return day <= DaysInMonth(time.Date(year, month, 1, 0, 0, 0, 0, time.UTC))
+}
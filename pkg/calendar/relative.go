@@ -0,0 +1,56 @@
+package calendar
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseRelativeDate converts a date relative to now, such as "tomorrow", "next fri", or "in 3 days",
+// to the date it refers to, returning false if the string is not a relative date
+func ParseRelativeDate(s string, now time.Time) (time.Time, bool) {
+	fields := strings.Fields(strings.ToLower(s))
+	switch len(fields) {
+	case 1:
+		switch fields[0] {
+		case "today":
+			return now, true
+		case "tomorrow":
+			return now.AddDate(0, 0, 1), true
+		}
+	case 2:
+		if fields[0] != "next" {
+			break
+		}
+		if fields[1] == "week" {
+			return now.AddDate(0, 0, 7), true
+		}
+		weekday, err := ParseWeekday(fields[1])
+		if err != nil {
+			break
+		}
+		// the next weekday is always in the future, so "next fri" on a Friday is a week away
+		days := DaysBetweenWeekdays(now.Weekday(), weekday)
+		if days == 0 {
+			days = 7
+		}
+		return now.AddDate(0, 0, days), true
+	case 3:
+		if fields[0] != "in" {
+			break
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 0 {
+			break
+		}
+		switch strings.TrimSuffix(fields[2], "s") {
+		case "day":
+			return now.AddDate(0, 0, n), true
+		case "week":
+			return now.AddDate(0, 0, 7*n), true
+		case "month":
+			return now.AddDate(0, n, 0), true
+		}
+	}
+	return time.Time{}, false
+}
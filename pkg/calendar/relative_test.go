@@ -0,0 +1,105 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRelativeDate(t *testing.T) {
+	// a Wednesday
+	now := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		s          string
+		expected   time.Time
+		expectedOk bool
+	}{
+		"today": {
+			s:          "today",
+			expected:   now,
+			expectedOk: true,
+		},
+		"tomorrow": {
+			s:          "Tomorrow",
+			expected:   time.Date(2024, time.March, 7, 12, 0, 0, 0, time.UTC),
+			expectedOk: true,
+		},
+		"next weekday later in the week": {
+			s:          "next fri",
+			expected:   time.Date(2024, time.March, 8, 12, 0, 0, 0, time.UTC),
+			expectedOk: true,
+		},
+		"next weekday earlier in the week": {
+			s:          "next Monday",
+			expected:   time.Date(2024, time.March, 11, 12, 0, 0, 0, time.UTC),
+			expectedOk: true,
+		},
+		"next weekday same as today": {
+			s:          "next wed",
+			expected:   time.Date(2024, time.March, 13, 12, 0, 0, 0, time.UTC),
+			expectedOk: true,
+		},
+		"next week": {
+			s:          "next week",
+			expected:   time.Date(2024, time.March, 13, 12, 0, 0, 0, time.UTC),
+			expectedOk: true,
+		},
+		"in days": {
+			s:          "in 3 days",
+			expected:   time.Date(2024, time.March, 9, 12, 0, 0, 0, time.UTC),
+			expectedOk: true,
+		},
+		"in one day": {
+			s:          "in 1 day",
+			expected:   time.Date(2024, time.March, 7, 12, 0, 0, 0, time.UTC),
+			expectedOk: true,
+		},
+		"in weeks": {
+			s:          "in 2 weeks",
+			expected:   time.Date(2024, time.March, 20, 12, 0, 0, 0, time.UTC),
+			expectedOk: true,
+		},
+		"in months": {
+			s:          "in 1 month",
+			expected:   time.Date(2024, time.April, 6, 12, 0, 0, 0, time.UTC),
+			expectedOk: true,
+		},
+		"weekday is not relative": {
+			s:          "fri",
+			expectedOk: false,
+		},
+		"invalid weekday": {
+			s:          "next funday",
+			expectedOk: false,
+		},
+		"invalid number": {
+			s:          "in three days",
+			expectedOk: false,
+		},
+		"negative number": {
+			s:          "in -3 days",
+			expectedOk: false,
+		},
+		"invalid unit": {
+			s:          "in 3 years",
+			expectedOk: false,
+		},
+		"absolute date": {
+			s:          "Mar 3 2024",
+			expectedOk: false,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result, ok := ParseRelativeDate(test.s, now)
+			if ok != test.expectedOk {
+				t.Fatalf("result ok %t not equal to expected ok %t", ok, test.expectedOk)
+			}
+			if !result.Equal(test.expected) {
+				t.Fatalf("result date %v not equal to expected date %v", result, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package completions
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/fileutil"
+)
+
+// format of occurrence dates in the log
+const dateFormat = "2006-01-02"
+
+// Record is an entry of the log recording that an occurrence of a task was completed or, if Undo is
+// set, that a previous completion was undone
+type Record struct {
+	ID   string    `json:"id"`
+	Date string    `json:"date"`
+	Text string    `json:"text,omitempty"`
+	Time time.Time `json:"time"`
+	Undo bool      `json:"undo,omitempty"`
+}
+
+// Log is an append-only log of completed task occurrences, each identified by the identifier of its
+// task and the date on which it occurs
+type Log struct {
+	path string
+	done map[occurrence]bool
+}
+
+type occurrence struct {
+	id   string
+	date string
+}
+
+// Open reads the log stored in a file, which is created when the first record is added
+func Open(path string) (*Log, error) {
+	l := &Log{
+		path: path,
+		done: make(map[occurrence]bool),
+	}
+
+	f, err := os.Open(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint
+
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		r := &Record{}
+		err := json.Unmarshal(scanner.Bytes(), r)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid record: %v", path, lineNum, err)
+		}
+		l.apply(r)
+	}
+	return l, scanner.Err()
+}
+
+// Done reports whether the occurrence of a task on a date has been completed
+func (l *Log) Done(id string, date time.Time) bool {
+	return l.done[occurrence{id: id, date: date.Format(dateFormat)}]
+}
+
+// LastDone returns the date of the most recent completed occurrence of a task
+func (l *Log) LastDone(id string) (time.Time, bool) {
+	return l.doneDate(id, func(date string, found string) bool { return date > found })
+}
+
+// FirstDone returns the date of the earliest completed occurrence of a task
+func (l *Log) FirstDone(id string) (time.Time, bool) {
+	return l.doneDate(id, func(date string, found string) bool { return date < found })
+}
+
+// doneDate returns the date of the completed occurrence of a task that is preferred over all others
+func (l *Log) doneDate(id string, prefer func(date string, found string) bool) (time.Time, bool) {
+	found := ""
+	for o := range l.done {
+		if o.id == id && (found == "" || prefer(o.date, found)) {
+			found = o.date
+		}
+	}
+	if found == "" {
+		return time.Time{}, false
+	}
+	date, err := time.ParseInLocation(dateFormat, found, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// Complete records that the occurrence of a task on a date has been completed
+func (l *Log) Complete(id string, date time.Time, text string) error {
+	return l.append(&Record{ID: id, Date: date.Format(dateFormat), Text: text, Time: time.Now()})
+}
+
+// Undo records that the completion of the occurrence of a task on a date has been undone
+func (l *Log) Undo(id string, date time.Time, text string) error {
+	return l.append(&Record{ID: id, Date: date.Format(dateFormat), Text: text, Time: time.Now(), Undo: true})
+}
+
+func (l *Log) append(r *Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	err = fileutil.AppendLine(l.path, data)
+	if err != nil {
+		return err
+	}
+
+	l.apply(r)
+	return nil
+}
+
+func (l *Log) apply(r *Record) {
+	o := occurrence{id: r.ID, date: r.Date}
+	if r.Undo {
+		delete(l.done, o)
+		return
+	}
+	l.done[o] = true
+}
@@ -0,0 +1,89 @@
+package completions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLog(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "data", "done.log")
+	mon := time.Date(2024, time.March, 4, 12, 0, 0, 0, time.Local)
+	nextMon := mon.AddDate(0, 0, 7)
+
+	l, err := Open(fp)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error opening missing log: %v", err)
+	}
+	if l.Done("abc1234", mon) {
+		t.Fatal("expected occurrence not to be done in empty log")
+	}
+
+	err = l.Complete("abc1234", mon, "gym")
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	err = l.Complete("def5678", mon, "laundry")
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	err = l.Undo("def5678", mon, "laundry")
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+
+	// check both the log in memory and the log read back from its file
+	reopened, err := Open(fp)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	for name, log := range map[string]*Log{"in memory": l, "reopened": reopened} {
+		if !log.Done("abc1234", mon) {
+			t.Fatalf("%s: expected completed occurrence to be done", name)
+		}
+		if log.Done("abc1234", nextMon) {
+			t.Fatalf("%s: expected other occurrence of completed task not to be done", name)
+		}
+		if log.Done("def5678", mon) {
+			t.Fatalf("%s: expected undone occurrence not to be done", name)
+		}
+		if last, ok := log.LastDone("abc1234"); !ok || last.Format(dateFormat) != mon.Format(dateFormat) {
+			t.Fatalf("%s: result last done %v not equal to expected last done %v", name, last, mon)
+		}
+		if _, ok := log.LastDone("def5678"); ok {
+			t.Fatalf("%s: expected undone task not to have a last done date", name)
+		}
+		if first, ok := log.FirstDone("abc1234"); !ok || first.Format(dateFormat) != mon.Format(dateFormat) {
+			t.Fatalf("%s: result first done %v not equal to expected first done %v", name, first, mon)
+		}
+		if _, ok := log.FirstDone("def5678"); ok {
+			t.Fatalf("%s: expected undone task not to have a first done date", name)
+		}
+	}
+
+	contents, err := os.ReadFile(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(contents), "\n"); n != 3 {
+		t.Fatalf("result number of records %d not equal to expected number of records %d", n, 3)
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "done.log")
+	err := os.WriteFile(fp, []byte("{\"id\":\"abc1234\",\"date\":\"2024-03-04\"}\nnot json\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Open(fp)
+	if err == nil {
+		t.Fatal("expected non-nil error")
+	}
+	if !strings.Contains(err.Error(), ":2:") {
+		t.Fatalf("result error [%v] does not contain expected line number", err)
+	}
+}
@@ -0,0 +1,31 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// AppendLine appends a line to a file while holding a lock on it, creating the file and its directory
+// if they do not exist
+func AppendLine(path string, line []byte) error {
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return err
+	}
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock() //nolint
+
+	f, err := os.OpenFile(filepath.Clean(path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	if err != nil {
+		f.Close() //nolint
+		return err
+	}
+	return f.Close()
+}
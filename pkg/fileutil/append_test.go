@@ -0,0 +1,29 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAppendLine(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "data", "log")
+
+	for _, line := range []string{"a", "b"} {
+		err := AppendLine(fp, []byte(line))
+		if err != nil {
+			t.Fatalf("unexpected non-nil error: %v", err)
+		}
+	}
+
+	contents, err := os.ReadFile(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(contents) != "a\nb\n" {
+		t.Fatalf("result contents '%s' not equal to expected contents '%s'", contents, "a\nb\n")
+	}
+	if _, err := os.Stat(fp + ".lock"); !os.IsNotExist(err) {
+		t.Fatal("expected lock file to be removed")
+	}
+}
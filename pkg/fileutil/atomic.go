@@ -0,0 +1,47 @@
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes data to a file by writing it to a temporary file in the same directory and
+// renaming the temporary file over the original, so that the file is never partially written. The
+// permissions of an existing file are preserved.
+func WriteFileAtomic(path string, data []byte) error {
+	perm := os.FileMode(0o600)
+	info, err := os.Stat(path)
+	if err == nil {
+		perm = info.Mode().Perm()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	// remove the temporary file if it is not renamed
+	defer os.Remove(tmp.Name()) //nolint
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close() //nolint
+		return err
+	}
+	err = tmp.Sync()
+	if err != nil {
+		tmp.Close() //nolint
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp.Name(), perm)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,49 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "tasks.txt")
+
+	err := WriteFileAtomic(fp, []byte("Mon: a\n"))
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+
+	err = os.Chmod(fp, 0o640)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WriteFileAtomic(fp, []byte("Tue: b\n"))
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+
+	contents, err := os.ReadFile(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(contents) != "Tue: b\n" {
+		t.Fatalf("result contents '%s' not equal to expected contents '%s'", contents, "Tue: b\n")
+	}
+	info, err := os.Stat(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Fatalf("result permissions %v not equal to expected permissions %v", info.Mode().Perm(), os.FileMode(0o640))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("temporary files were not removed: %v", entries)
+	}
+}
@@ -0,0 +1,52 @@
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockTimeout is the maximum time to wait to acquire a lock held by another process
+var lockTimeout = 5 * time.Second
+
+// interval between attempts to acquire a lock
+const lockRetryInterval = 50 * time.Millisecond
+
+// Lock acquires an exclusive lock on a file by creating a lock file next to it, waiting for a lock held
+// by another process to be released, and returns a function that releases the lock
+func Lock(path string) (func() error, error) {
+	lockPath := lockFile(path)
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, err = fmt.Fprintf(f, "%d\n", os.Getpid())
+			closeErr := f.Close()
+			if err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(lockPath) //nolint
+				return nil, err
+			}
+			return func() error {
+				return os.Remove(lockPath)
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock on %s: remove %s if no other process is using it", path, lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// lockFile returns the path of the lock file of a file, which is hidden so that it is not discovered as a
+// task source file
+func lockFile(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".lock")
+}
@@ -0,0 +1,67 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	defer func(timeout time.Duration) { lockTimeout = timeout }(lockTimeout)
+	lockTimeout = 100 * time.Millisecond
+
+	fp := filepath.Join(t.TempDir(), "tasks.txt")
+
+	unlock, err := Lock(fp)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(fp), ".tasks.txt.lock")); err != nil {
+		t.Fatalf("expected lock file to exist: %v", err)
+	}
+
+	_, err = Lock(fp)
+	if err == nil {
+		t.Fatal("expected non-nil error acquiring held lock")
+	}
+
+	err = unlock()
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(fp), ".tasks.txt.lock")); !os.IsNotExist(err) {
+		t.Fatal("expected lock file to be removed")
+	}
+
+	unlock, err = Lock(fp)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error acquiring released lock: %v", err)
+	}
+	err = unlock()
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+}
+
+func TestLockWaits(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "tasks.txt")
+
+	unlock, err := Lock(fp)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	go func() {
+		time.Sleep(2 * lockRetryInterval)
+		unlock() //nolint
+	}()
+
+	unlock, err = Lock(fp)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error waiting for lock: %v", err)
+	}
+	err = unlock()
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package snooze
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/fileutil"
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks"
+)
+
+// format of dates in the store
+const dateFormat = "2006-01-02"
+
+// Record is an entry of the store recording that the occurrence of a task on Date was moved to To or,
+// if Clear is set, that a previous move was cleared
+type Record struct {
+	ID    string    `json:"id"`
+	Date  string    `json:"date"`
+	To    string    `json:"to,omitempty"`
+	Text  string    `json:"text,omitempty"`
+	Time  time.Time `json:"time"`
+	Clear bool      `json:"clear,omitempty"`
+}
+
+// Store is an append-only log of snoozed task occurrences, each identified by the identifier of its
+// task and the date on which it originally occurs
+type Store struct {
+	path    string
+	records map[occurrence]*Record
+}
+
+type occurrence struct {
+	id   string
+	date string
+}
+
+// Open reads the store from a file, which is created when the first record is added
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:    path,
+		records: make(map[occurrence]*Record),
+	}
+
+	f, err := os.Open(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint
+
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		r := &Record{}
+		err := json.Unmarshal(scanner.Bytes(), r)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid record: %v", path, lineNum, err)
+		}
+		s.apply(r)
+	}
+	return s, scanner.Err()
+}
+
+// Snooze moves the occurrence of a task on a date to another date
+func (s *Store) Snooze(id string, date time.Time, to time.Time, text string) error {
+	return s.append(&Record{ID: id, Date: date.Format(dateFormat), To: to.Format(dateFormat), Text: text, Time: time.Now()})
+}
+
+// Clear returns the occurrence of a task on a date to its original date
+func (s *Store) Clear(id string, date time.Time, text string) error {
+	return s.append(&Record{ID: id, Date: date.Format(dateFormat), Text: text, Time: time.Now(), Clear: true})
+}
+
+// Overrides returns the moved occurrences of a task, with dates in the local time zone at noon
+func (s *Store) Overrides(id string) []tasks.Override {
+	overrides := []tasks.Override{}
+	for _, r := range s.List() {
+		if r.ID != id {
+			continue
+		}
+		date, err := parseDate(r.Date)
+		if err != nil {
+			continue
+		}
+		to, err := parseDate(r.To)
+		if err != nil {
+			continue
+		}
+		overrides = append(overrides, tasks.Override{Date: date, To: to})
+	}
+	return overrides
+}
+
+// List returns the records of the moved occurrences of tasks, sorted by the date to which they were moved
+func (s *Store) List() []*Record {
+	records := []*Record{}
+	for _, r := range s.records {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].To != records[j].To {
+			return records[i].To < records[j].To
+		}
+		if records[i].Date != records[j].Date {
+			return records[i].Date < records[j].Date
+		}
+		return records[i].ID < records[j].ID
+	})
+	return records
+}
+
+func (s *Store) append(r *Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	err = fileutil.AppendLine(s.path, data)
+	if err != nil {
+		return err
+	}
+	s.apply(r)
+	return nil
+}
+
+func (s *Store) apply(r *Record) {
+	o := occurrence{id: r.ID, date: r.Date}
+	if r.Clear {
+		delete(s.records, o)
+		return
+	}
+	s.records[o] = r
+}
+
+func parseDate(date string) (time.Time, error) {
+	d, err := time.ParseInLocation(dateFormat, date, time.Local)
+	if err != nil {
+		return d, err
+	}
+	return d.Add(12 * time.Hour), nil
+}
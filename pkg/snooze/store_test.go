@@ -0,0 +1,83 @@
+package snooze
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "data", "snooze.log")
+	sat := time.Date(2024, time.May, 11, 12, 0, 0, 0, time.Local)
+	sun := sat.AddDate(0, 0, 1)
+	mon := sat.AddDate(0, 0, 2)
+
+	s, err := Open(fp)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error opening missing store: %v", err)
+	}
+	if len(s.Overrides("abc1234")) != 0 {
+		t.Fatal("expected no overrides in empty store")
+	}
+
+	err = s.Snooze("abc1234", sat, sun, "gutters")
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	// snoozing an occurrence again replaces its previous date
+	err = s.Snooze("abc1234", sat, mon, "gutters")
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	err = s.Snooze("def5678", sat, sun, "laundry")
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	err = s.Clear("def5678", sat, "laundry")
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+
+	reopened, err := Open(fp)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	for name, store := range map[string]*Store{"in memory": s, "reopened": reopened} {
+		overrides := store.Overrides("abc1234")
+		if len(overrides) != 1 {
+			t.Fatalf("%s: result number of overrides %d not equal to expected number of overrides %d", name, len(overrides), 1)
+		}
+		if !overrides[0].Date.Equal(sat) || !overrides[0].To.Equal(mon) {
+			t.Fatalf("%s: result override %v not equal to expected override from %v to %v", name, overrides[0], sat, mon)
+		}
+		if len(store.Overrides("def5678")) != 0 {
+			t.Fatalf("%s: expected cleared override to be removed", name)
+		}
+		if len(store.List()) != 1 {
+			t.Fatalf("%s: result number of records %d not equal to expected number of records %d", name, len(store.List()), 1)
+		}
+	}
+
+	contents, err := os.ReadFile(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(contents), "\n"); n != 4 {
+		t.Fatalf("result number of records %d not equal to expected number of records %d", n, 4)
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "snooze.log")
+	err := os.WriteFile(fp, []byte("not json\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Open(fp)
+	if err == nil {
+		t.Fatal("expected non-nil error")
+	}
+}
@@ -0,0 +1,58 @@
+package tasks
+
+import (
+	"fmt"
+	"time"
+)
+
+// deadlineTask is implemented by tasks that are displayed every day from a warning date until they are due
+type deadlineTask interface {
+	Task
+	// WarningDays returns the number of days before the due date from which the task is displayed
+	WarningDays() int
+}
+
+// Countdown is a deadline task displayed a number of days before it is due
+type Countdown struct {
+	Task
+	// Days is the number of days until the task is due
+	Days int
+}
+
+func (c *Countdown) String() string {
+	switch c.Days {
+	case 0:
+		return fmt.Sprintf("%s due today", c.Task)
+	case 1:
+		return fmt.Sprintf("%s due tomorrow", c.Task)
+	}
+	return fmt.Sprintf("%s due in %d days", c.Task, c.Days)
+}
+
+// Occurrence returns the date on which the task of a countdown displayed on a date is due
+func (c *Countdown) Occurrence(date time.Time) time.Time {
+	return date.AddDate(0, 0, c.Days)
+}
+
+// addCountdown adds a Countdown of a deadline task to each of the Processor's days from its warning date
+// until it is due
+func (p *Processor) addCountdown(d deadlineTask, overrides []Override) {
+	due := daysBetween(p.now, dueDate(d, p.now, overrides).To)
+	for day := due - d.WarningDays(); day <= due; day++ {
+		if day >= 0 && day <= p.maxDays {
+			p.addToDay(day, &Countdown{Task: d, Days: due - day})
+		}
+	}
+}
+
+// dueDate returns the due date of a deadline task as an Override from the date on which it is due to the
+// date to which it was moved, if any
+func dueDate(d deadlineTask, date time.Time, overrides []Override) Override {
+	due := date.AddDate(0, 0, d.DaysFrom(date))
+	for _, o := range overrides {
+		if daysBetween(due, o.Date) == 0 {
+			return o
+		}
+	}
+	return Override{Date: due, To: due}
+}
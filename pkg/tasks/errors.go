@@ -0,0 +1,59 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+)
+
+// LoadError is an error encountered while loading tasks from a source file, reporting the file
+// path and, when known, the (1-indexed) line and column at which it occurred
+type LoadError struct {
+	Path   string
+	Line   int
+	Column int
+	Err    error
+
+	// syntax is set for errors in the syntax of a line, as opposed to errors in its dates
+	syntax bool
+	// header is set for errors in the header directives of a file
+	header bool
+	// reference is set for errors in the labels to which relative tasks refer
+	reference bool
+}
+
+func (e *LoadError) Error() string {
+	switch {
+	case e.Line == 0:
+		return fmt.Sprintf("%s: %v", e.Path, e.Err)
+	case e.Column == 0:
+		return fmt.Sprintf("%s:%d: %v", e.Path, e.Line, e.Err)
+	default:
+		return fmt.Sprintf("%s:%d:%d: %v", e.Path, e.Line, e.Column, e.Err)
+	}
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// sortErrors sorts errors by file path, line, and column, with errors that are not LoadErrors first
+func sortErrors(errs []error) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		ei, iok := errs[i].(*LoadError)
+		ej, jok := errs[j].(*LoadError)
+		if !iok || !jok {
+			return !iok && jok
+		}
+		if ei.Path != ej.Path {
+			return ei.Path < ej.Path
+		}
+		if ei.Line != ej.Line {
+			return ei.Line < ej.Line
+		}
+		return ei.Column < ej.Column
+	})
+}
+
+// errHandler handles an error encountered while loading tasks, returning a non-nil error to stop
+// loading or nil to continue
+type errHandler func(error) error
@@ -0,0 +1,36 @@
+package tasks
+
+import (
+	"errors"
+	"testing"
+)
+
+// failFast is an errHandler that stops loading at the first error
+func failFast(err error) error {
+	return err
+}
+
+func TestSortErrors(t *testing.T) {
+	other := errors.New("other")
+	errs := []error{
+		&LoadError{Path: "b.txt", Line: 1, Err: other},
+		&LoadError{Path: "a.txt", Line: 2, Column: 5, Err: other},
+		&LoadError{Path: "a.txt", Line: 2, Column: 1, Err: other},
+		other,
+		&LoadError{Path: "a.txt", Err: other},
+	}
+	expected := []string{
+		"other",
+		"a.txt: other",
+		"a.txt:2:1: other",
+		"a.txt:2:5: other",
+		"b.txt:1: other",
+	}
+
+	sortErrors(errs)
+	for i, err := range errs {
+		if err.Error() != expected[i] {
+			t.Fatalf("result error %d '%v' not equal to expected '%s'", i, err, expected[i])
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package tasks
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ArchiveSuffix is added to the name of a source file to name the default file to which the prune
+// command archives its past tasks. Files with the suffix are never discovered as source files in a
+// directory or glob, since their tasks would otherwise be loaded again after they were pruned.
+const ArchiveSuffix = ".archive"
+
+// fileFilter holds patterns for files to include and exclude when expanding directories and globs
+type fileFilter struct {
+	include []string
+	exclude []string
+}
+
+// match reports whether a file passes the filter, where a pattern containing a path separator is
+// matched against the full path and any other pattern is matched against the base name
+func (f *fileFilter) match(name string) (bool, error) {
+	if len(f.include) > 0 {
+		ok, err := matchAny(f.include, name)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	ok, err := matchAny(f.exclude, name)
+	if err != nil {
+		return false, err
+	}
+	return !ok, nil
+}
+
+func matchAny(patterns []string, name string) (bool, error) {
+	slashName := filepath.ToSlash(name)
+	for _, pattern := range patterns {
+		var ok bool
+		var err error
+		if strings.Contains(pattern, "/") {
+			ok, err = matchPath(filepath.ToSlash(filepath.Clean(pattern)), slashName)
+		} else {
+			ok, err = path.Match(pattern, path.Base(slashName))
+		}
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// expandSources expands source entries, which can be files, directories, shell globs, or recursive
+// globs containing **, into a list of files. Files discovered from directories and globs are sorted
+// and filtered, excluding archives of pruned tasks, while files listed explicitly are always included.
+// The order of the entries is preserved and duplicate files are removed.
+func expandSources(entries []string, filter *fileFilter) ([]string, error) {
+	files := []string{}
+	seen := make(map[string]bool)
+
+	for _, entry := range entries {
+		expanded, err := expandSource(entry, filter)
+		if err != nil {
+			return files, err
+		}
+		for _, fp := range expanded {
+			if seen[fp] {
+				continue
+			}
+			seen[fp] = true
+			files = append(files, fp)
+		}
+	}
+	return files, nil
+}
+
+func expandSource(entry string, filter *fileFilter) ([]string, error) {
+	entry = filepath.Clean(entry)
+
+	var found []string
+	var err error
+	switch {
+	case strings.Contains(filepath.ToSlash(entry), "**"):
+		found, err = globRecursive(entry)
+	case isGlob(entry):
+		found, err = globFiles(entry)
+	default:
+		info, statErr := os.Stat(entry)
+		if statErr != nil || !info.IsDir() {
+			// a missing file is returned as is so that the error is reported when it is opened
+			return []string{entry}, nil
+		}
+		found, err = walkFiles(entry, func(string) (bool, error) { return true, nil })
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 && isGlob(entry) {
+		// a glob matching no files is returned as is so that the error is reported when it is opened
+		return []string{entry}, nil
+	}
+
+	files := []string{}
+	for _, fp := range found {
+		// pruned tasks are archived next to their source file and must not be loaded again
+		if strings.HasSuffix(fp, ArchiveSuffix) {
+			continue
+		}
+		ok, err := filter.match(fp)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			files = append(files, fp)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// isGlob reports whether a source entry is a shell glob or recursive glob
+func isGlob(entry string) bool {
+	return strings.ContainsAny(entry, "*?[")
+}
+
+// openSource opens a source file, reporting a glob that matches no files, which is not expanded
+func openSource(fp string) (*os.File, error) {
+	f, err := os.Open(filepath.Clean(fp))
+	if errors.Is(err, fs.ErrNotExist) && isGlob(fp) {
+		return nil, errors.New("no files match the glob")
+	}
+	return f, err
+}
+
+// globFiles returns the files, excluding directories, matching a shell glob, skipping hidden files
+// unless the glob itself starts with a dot as in a shell
+func globFiles(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	hidden := strings.HasPrefix(filepath.Base(pattern), ".")
+	files := []string{}
+	for _, match := range matches {
+		if !hidden && strings.HasPrefix(filepath.Base(match), ".") {
+			continue
+		}
+		info, err := os.Stat(match)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, match)
+	}
+	return files, nil
+}
+
+// globRecursive returns the files matching a glob in which ** matches any number of directories
+func globRecursive(pattern string) ([]string, error) {
+	slashPattern := filepath.ToSlash(pattern)
+
+	// walk from the longest leading path that does not contain any glob characters
+	segments := strings.Split(slashPattern, "/")
+	rootSegments := []string{}
+	for _, segment := range segments {
+		if strings.ContainsAny(segment, "*?[") {
+			break
+		}
+		rootSegments = append(rootSegments, segment)
+	}
+	root := strings.Join(rootSegments, "/")
+	if root == "" {
+		root = "."
+		if strings.HasPrefix(slashPattern, "/") {
+			root = "/"
+		}
+	}
+
+	return walkFiles(filepath.FromSlash(root), func(fp string) (bool, error) {
+		return matchPath(slashPattern, filepath.ToSlash(fp))
+	})
+}
+
+// walkFiles recursively returns the files under a directory accepted by a match function, skipping
+// hidden files and directories
+func walkFiles(root string, match func(string) (bool, error)) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(root, func(fp string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrPermission) {
+				return nil
+			}
+			return err
+		}
+		if fp != root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		ok, err := match(fp)
+		if err != nil {
+			return err
+		}
+		if ok {
+			files = append(files, fp)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return files, nil
+	}
+	return files, err
+}
+
+// matchPath matches a slash-separated path against a pattern in which ** matches zero or more
+// path segments and all other segments follow the syntax of path.Match
+func matchPath(pattern, name string) (bool, error) {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) (bool, error) {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				ok, err := matchSegments(pattern[1:], name[i:])
+				if err != nil || ok {
+					return ok, err
+				}
+			}
+			return false, nil
+		}
+		if len(name) == 0 {
+			return false, nil
+		}
+		ok, err := path.Match(pattern[0], name[0])
+		if err != nil || !ok {
+			return false, err
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0, nil
+}
@@ -0,0 +1,201 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandSources(t *testing.T) {
+	root := t.TempDir()
+	for _, fp := range []string{
+		"a.txt",
+		"b.txt",
+		"notes.md",
+		"sub/c.txt",
+		"sub/deep/d.txt",
+		"sub/deep/e.bak",
+		"sub/deep/d.txt.archive",
+		".hidden/f.txt",
+		".a.txt",
+		".a.txt.lock",
+	} {
+		fp = filepath.Join(root, filepath.FromSlash(fp))
+		if err := os.MkdirAll(filepath.Dir(fp), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fp, []byte{}, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	join := func(fps ...string) []string {
+		joined := []string{}
+		for _, fp := range fps {
+			joined = append(joined, filepath.Join(root, filepath.FromSlash(fp)))
+		}
+		return joined
+	}
+
+	tests := map[string]struct {
+		entries  []string
+		filter   *fileFilter
+		expected []string
+	}{
+		"file": {
+			entries:  join("b.txt"),
+			filter:   &fileFilter{},
+			expected: join("b.txt"),
+		},
+		"missing file": {
+			entries:  join("missing.txt"),
+			filter:   &fileFilter{},
+			expected: join("missing.txt"),
+		},
+		"directory": {
+			entries:  join("sub"),
+			filter:   &fileFilter{},
+			expected: join("sub/c.txt", "sub/deep/d.txt", "sub/deep/e.bak"),
+		},
+		"directory skips hidden": {
+			entries:  join(""),
+			filter:   &fileFilter{include: []string{"*.txt"}},
+			expected: join("a.txt", "b.txt", "sub/c.txt", "sub/deep/d.txt"),
+		},
+		"glob": {
+			entries:  join("*.txt"),
+			filter:   &fileFilter{},
+			expected: join("a.txt", "b.txt"),
+		},
+		"glob skips hidden": {
+			entries:  join("*"),
+			filter:   &fileFilter{},
+			expected: join("a.txt", "b.txt", "notes.md"),
+		},
+		"glob of hidden files": {
+			entries:  join(".*.txt"),
+			filter:   &fileFilter{},
+			expected: join(".a.txt"),
+		},
+		"recursive glob": {
+			entries:  join("**/*.txt"),
+			filter:   &fileFilter{},
+			expected: join("a.txt", "b.txt", "sub/c.txt", "sub/deep/d.txt"),
+		},
+		"recursive glob with trailing segment": {
+			entries:  join("sub/**/d.txt"),
+			filter:   &fileFilter{},
+			expected: join("sub/deep/d.txt"),
+		},
+		"explicit archive": {
+			entries:  join("sub/deep/d.txt.archive"),
+			filter:   &fileFilter{},
+			expected: join("sub/deep/d.txt.archive"),
+		},
+		"glob without matches": {
+			entries:  join("*.json", "sub/**/*.json"),
+			filter:   &fileFilter{},
+			expected: join("*.json", "sub/**/*.json"),
+		},
+		"glob with matches excluded": {
+			entries:  join("*.md"),
+			filter:   &fileFilter{exclude: []string{"*.md"}},
+			expected: join(),
+		},
+		"glob skips archives": {
+			entries:  join("sub/deep/*"),
+			filter:   &fileFilter{},
+			expected: join("sub/deep/d.txt", "sub/deep/e.bak"),
+		},
+		"exclude": {
+			entries:  join("sub"),
+			filter:   &fileFilter{exclude: []string{"*.bak"}},
+			expected: join("sub/c.txt", "sub/deep/d.txt"),
+		},
+		"exclude path pattern": {
+			entries:  join("sub"),
+			filter:   &fileFilter{exclude: []string{"**/deep/**"}},
+			expected: join("sub/c.txt"),
+		},
+		"explicit file is not filtered": {
+			entries:  join("notes.md"),
+			filter:   &fileFilter{include: []string{"*.txt"}},
+			expected: join("notes.md"),
+		},
+		"entry order preserved and duplicates removed": {
+			entries:  join("sub/c.txt", "*.txt", "sub/**"),
+			filter:   &fileFilter{include: []string{"*.txt"}},
+			expected: join("sub/c.txt", "a.txt", "b.txt", "sub/deep/d.txt"),
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result, err := expandSources(test.entries, test.filter)
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if len(result) != len(test.expected) {
+				t.Fatalf("result files %v not equal to expected files %v", result, test.expected)
+			}
+			for i := range result {
+				if result[i] != test.expected[i] {
+					t.Fatalf("result files %v not equal to expected files %v", result, test.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := map[string]struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		"exact": {
+			pattern:  "a/b.txt",
+			name:     "a/b.txt",
+			expected: true,
+		},
+		"double star matches zero segments": {
+			pattern:  "a/**/b.txt",
+			name:     "a/b.txt",
+			expected: true,
+		},
+		"double star matches many segments": {
+			pattern:  "a/**/b.txt",
+			name:     "a/x/y/z/b.txt",
+			expected: true,
+		},
+		"single star does not cross segments": {
+			pattern:  "a/*.txt",
+			name:     "a/x/b.txt",
+			expected: false,
+		},
+		"trailing double star": {
+			pattern:  "a/**",
+			name:     "a/x/b.txt",
+			expected: true,
+		},
+		"no match": {
+			pattern:  "a/**/c.txt",
+			name:     "a/x/b.txt",
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result, err := matchPath(test.pattern, test.name)
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if result != test.expected {
+				t.Fatalf("result %t not equal to expected %t", result, test.expected)
+			}
+		})
+	}
+}
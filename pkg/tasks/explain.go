@@ -0,0 +1,120 @@
+package tasks
+
+import (
+	"sort"
+	"time"
+)
+
+// maximum number of days between consecutive occurrences of any task that is not floating
+const maxDaysBetweenAnyOccurrences = 366
+
+// Explainer is implemented by tasks that can describe how the dates of their occurrences are calculated
+type Explainer interface {
+	// Rule describes the dates on which the task occurs
+	Rule() string
+	// Adjustment describes why the task occurs on a date other than the one set by its rule, which is
+	// empty if it does not
+	Adjustment(date time.Time) string
+}
+
+// ExplainedOccurrence is an occurrence of a task with a description of how its date was calculated
+type ExplainedOccurrence struct {
+	Override
+	// Adjustment describes why the task occurs on a date other than the one set by its rule, such as a
+	// day that does not exist in a month rolling over into the next month
+	Adjustment string
+}
+
+// Moved reports whether the occurrence was moved to another date by an override
+func (o ExplainedOccurrence) Moved() bool {
+	return daysBetween(o.Date, o.To) != 0
+}
+
+// Explanation describes why a task does or does not appear on a date
+type Explanation struct {
+	Task Task
+	Date time.Time
+	// Rule describes the dates on which the task occurs
+	Rule string
+	// Occurrences are the occurrences of the task around the date, ordered by their original dates
+	Occurrences []ExplainedOccurrence
+}
+
+// Explain calculates the Explanation of a task on a date, with up to n occurrences before and n
+// occurrences on or after the date
+func Explain(t Task, date time.Time, n int, overrides OverrideStore) *Explanation {
+	e := &Explanation{
+		Task:        t,
+		Date:        date,
+		Occurrences: []ExplainedOccurrence{},
+	}
+	explainer, ok := t.(Explainer)
+	if ok {
+		e.Rule = explainer.Rule()
+	}
+
+	maxDays := n * maxDaysBetweenAnyOccurrences
+	dates := []time.Time{}
+	before := occurrences(t, date, n, maxDays, -1)
+	for i := len(before) - 1; i >= 0; i-- {
+		dates = append(dates, before[i])
+	}
+	dates = append(dates, occurrences(t, date, n, maxDays, 1)...)
+
+	moved := overridesOf(t, overrides)
+	for _, d := range dates {
+		o := ExplainedOccurrence{Override: Override{Date: d, To: d}}
+		for _, m := range moved {
+			if daysBetween(m.Date, d) == 0 {
+				o.To = m.To
+			}
+		}
+		if ok {
+			o.Adjustment = explainer.Adjustment(d)
+		}
+		e.Occurrences = append(e.Occurrences, o)
+	}
+
+	// include occurrences from outside of the searched dates that were moved into them
+	if len(dates) > 0 {
+		first, last := dates[0], dates[len(dates)-1]
+		for _, m := range moved {
+			if daysBetween(m.Date, first) <= 0 && daysBetween(m.Date, last) >= 0 {
+				continue
+			}
+			if daysBetween(first, m.To) < 0 || daysBetween(m.To, last) < 0 {
+				continue
+			}
+			o := ExplainedOccurrence{Override: m}
+			if ok {
+				o.Adjustment = explainer.Adjustment(m.Date)
+			}
+			e.Occurrences = append(e.Occurrences, o)
+		}
+	}
+	sort.SliceStable(e.Occurrences, func(i, j int) bool {
+		return e.Occurrences[i].Date.Before(e.Occurrences[j].Date)
+	})
+	return e
+}
+
+// On returns the occurrence of the task displayed on the explained date, if any
+func (e *Explanation) On() (ExplainedOccurrence, bool) {
+	for _, o := range e.Occurrences {
+		if daysBetween(o.To, e.Date) == 0 {
+			return o, true
+		}
+	}
+	return ExplainedOccurrence{}, false
+}
+
+// MovedFrom returns the occurrence of the task on the explained date that was moved to another date,
+// if any
+func (e *Explanation) MovedFrom() (ExplainedOccurrence, bool) {
+	for _, o := range e.Occurrences {
+		if daysBetween(o.Date, e.Date) == 0 && o.Moved() {
+			return o, true
+		}
+	}
+	return ExplainedOccurrence{}, false
+}
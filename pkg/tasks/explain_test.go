@@ -0,0 +1,121 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+func TestExplain(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+	}
+	format := func(occurrences []ExplainedOccurrence) string {
+		dates := []string{}
+		for _, o := range occurrences {
+			d := o.Date.Format("2006-01-02")
+			if o.Moved() {
+				d += ">" + o.To.Format("2006-01-02")
+			}
+			if o.Adjustment != "" {
+				d += "*"
+			}
+			dates = append(dates, d)
+		}
+		return strings.Join(dates, " ")
+	}
+
+	tests := map[string]struct {
+		date       string
+		on         time.Time
+		n          int
+		overrides  testOverrides
+		expected   string
+		expectedOn bool
+	}{
+		"weekly": {
+			date:       "Sun",
+			on:         date(2024, time.March, 3),
+			n:          2,
+			overrides:  testOverrides{},
+			expected:   "2024-02-18 2024-02-25 2024-03-03 2024-03-10",
+			expectedOn: true,
+		},
+		"monthly on the 31st in a leap year": {
+			date:       "31",
+			on:         date(2024, time.March, 3),
+			n:          2,
+			overrides:  testOverrides{},
+			expected:   "2024-01-31 2024-03-02* 2024-03-31 2024-05-01*",
+			expectedOn: false,
+		},
+		"monthly on the 31st in a year that is not a leap year": {
+			date:       "31",
+			on:         date(2023, time.March, 3),
+			n:          1,
+			overrides:  testOverrides{},
+			expected:   "2023-01-31 2023-03-03*",
+			expectedOn: true,
+		},
+		"annual on Feb 29": {
+			date:       "Feb 29",
+			on:         date(2025, time.March, 1),
+			n:          2,
+			overrides:  testOverrides{},
+			expected:   "2023-03-01* 2024-02-29 2025-03-01* 2026-03-01*",
+			expectedOn: true,
+		},
+		"single in the past": {
+			date:       "Jan 5 2024",
+			on:         date(2024, time.March, 3),
+			n:          2,
+			overrides:  testOverrides{},
+			expected:   "2024-01-05",
+			expectedOn: false,
+		},
+		"moved onto the date": {
+			date:       "Sat",
+			on:         date(2024, time.March, 3),
+			n:          1,
+			overrides:  testOverrides{"id": {{Date: date(2024, time.March, 2), To: date(2024, time.March, 3)}}},
+			expected:   "2024-03-02>2024-03-03 2024-03-09",
+			expectedOn: true,
+		},
+		"moved off the date": {
+			date:       "Sun",
+			on:         date(2024, time.March, 3),
+			n:          1,
+			overrides:  testOverrides{"id": {{Date: date(2024, time.March, 3), To: date(2024, time.March, 5)}}},
+			expected:   "2024-02-25 2024-03-03>2024-03-05",
+			expectedOn: false,
+		},
+		"moved from outside of the searched dates": {
+			date:       "Jan 5",
+			on:         date(2024, time.March, 3),
+			n:          1,
+			overrides:  testOverrides{"id": {{Date: date(2024, time.January, 5), To: date(2024, time.March, 3)}}},
+			expected:   "2024-01-05>2024-03-03 2025-01-05",
+			expectedOn: true,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			tsk, err := newDetectedTask(&sources.RawTask{Date: test.date, Text: "task", ID: "id"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			e := Explain(tsk, test.on, test.n, test.overrides)
+			result := format(e.Occurrences)
+			if result != test.expected {
+				t.Fatalf("result occurrences %s not equal to expected occurrences %s", result, test.expected)
+			}
+			if _, on := e.On(); on != test.expectedOn {
+				t.Fatalf("result on %t not equal to expected on %t", on, test.expectedOn)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package tasks
+
+import (
+	"sort"
+	"strings"
+)
+
+// Tagged is implemented by tasks with tags and contexts parsed from their text
+type Tagged interface {
+	Tags() []string
+	Contexts() []string
+}
+
+// Labeled is implemented by tasks with the label of the source file from which they were loaded
+type Labeled interface {
+	Label() string
+}
+
+// Filter selects tasks by their tags, contexts, priority, and label, keeping a task that has any of Tags,
+// none of ExcludeTags, any of Contexts, a priority at least as high as MinPriority, and any of Labels, with
+// an empty list or zero MinPriority placing no restriction on the tasks
+type Filter struct {
+	Tags        []string
+	ExcludeTags []string
+	Contexts    []string
+	MinPriority int
+	Labels      []string
+}
+
+// Match reports whether a task is selected by the Filter
+func (f *Filter) Match(t Task) bool {
+	var tags, contexts []string
+	if tagged, ok := t.(Tagged); ok {
+		tags = tagged.Tags()
+		contexts = tagged.Contexts()
+	}
+
+	if len(f.Tags) > 0 && !containsAny(tags, f.Tags, "#") {
+		return false
+	}
+	if containsAny(tags, f.ExcludeTags, "#") {
+		return false
+	}
+	if len(f.Contexts) > 0 && !containsAny(contexts, f.Contexts, "@") {
+		return false
+	}
+	if f.MinPriority > 0 {
+		if p := PriorityOf(t); p == 0 || p > f.MinPriority {
+			return false
+		}
+	}
+	if len(f.Labels) > 0 && !containsAny([]string{strings.ToLower(LabelOf(t))}, f.Labels, "") {
+		return false
+	}
+	return true
+}
+
+// LabelOf returns the label of a task, or an empty string for a task without a label, which for a Reminder
+// or Countdown is the label of its task
+func LabelOf(t Task) string {
+	if l, ok := Unwrap(t).(Labeled); ok {
+		return l.Label()
+	}
+	return ""
+}
+
+// containsAny reports whether names contains any of the wanted names, which are compared without case
+// and with an optional prefix removed
+func containsAny(names []string, wanted []string, prefix string) bool {
+	for _, w := range wanted {
+		w = strings.ToLower(strings.TrimPrefix(w, prefix))
+		for _, name := range names {
+			if name == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TagCount is the number of occurrences of tasks with a tag, written as #tag, or a context, written as @context
+type TagCount struct {
+	Name  string
+	Count int
+}
+
+// countTags counts the tags and contexts of tasks, sorted with tags before contexts and then by name
+func countTags(tsks []Task) []*TagCount {
+	counts := make(map[string]int)
+	for _, t := range tsks {
+		tagged, ok := Unwrap(t).(Tagged)
+		if !ok {
+			continue
+		}
+		for _, tag := range tagged.Tags() {
+			counts["#"+tag]++
+		}
+		for _, c := range tagged.Contexts() {
+			counts["@"+c]++
+		}
+	}
+
+	tags := []*TagCount{}
+	for name, count := range counts {
+		tags = append(tags, &TagCount{Name: name, Count: count})
+	}
+	// # sorts before @
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	return tags
+}
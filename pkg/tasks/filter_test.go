@@ -0,0 +1,176 @@
+package tasks
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+func TestFilter(t *testing.T) {
+	tsk, err := newDetectedTask(&sources.RawTask{Date: "Mon", Text: "deploy freeze #work #release @office"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		filter   *Filter
+		expected bool
+	}{
+		"empty filter": {
+			filter:   &Filter{},
+			expected: true,
+		},
+		"tag": {
+			filter:   &Filter{Tags: []string{"work"}},
+			expected: true,
+		},
+		"tag with prefix and capitals": {
+			filter:   &Filter{Tags: []string{"#Work"}},
+			expected: true,
+		},
+		"any of tags": {
+			filter:   &Filter{Tags: []string{"home", "release"}},
+			expected: true,
+		},
+		"missing tag": {
+			filter:   &Filter{Tags: []string{"home"}},
+			expected: false,
+		},
+		"excluded tag": {
+			filter:   &Filter{ExcludeTags: []string{"release"}},
+			expected: false,
+		},
+		"tag and excluded tag": {
+			filter:   &Filter{Tags: []string{"work"}, ExcludeTags: []string{"release"}},
+			expected: false,
+		},
+		"context": {
+			filter:   &Filter{Contexts: []string{"@office"}},
+			expected: true,
+		},
+		"missing context": {
+			filter:   &Filter{Contexts: []string{"home"}},
+			expected: false,
+		},
+		"tag is not a context": {
+			filter:   &Filter{Contexts: []string{"work"}},
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result := test.filter.Match(tsk)
+			if result != test.expected {
+				t.Fatalf("result match %t not equal to expected match %t", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestFilterLabel(t *testing.T) {
+	header := &sources.Header{Label: "Work"}
+	labeled, err := newDetectedTask(&sources.RawTask{Date: "Mon", Text: "deploy", Header: header})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlabeled, err := newDetectedTask(&sources.RawTask{Date: "Mon", Text: "laundry"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filter := &Filter{Labels: []string{"home", "work"}}
+	if !filter.Match(labeled) {
+		t.Fatal("expected task with label to match")
+	}
+	if filter.Match(unlabeled) {
+		t.Fatal("expected task without label not to match")
+	}
+}
+
+func TestProcessorFilterTags(t *testing.T) {
+	// a Wednesday
+	today := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+
+	p := NewProcessor(today, 7, nil, nil)
+	p.SetFilter(&Filter{ExcludeTags: []string{"kids"}})
+	for _, line := range []string{
+		"Wed: standup #work @office",
+		"Thu: deploy #work #release",
+		"Fri: soccer practice #kids",
+		"Mar 20: dentist #home",
+		"Sat: laundry",
+		"due 2024-03-11 from 2024-03-08: taxes #home",
+	} {
+		raws, err := sources.ParseLine(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tsk, err := newDetectedTask(raws[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.add(tsk)
+	}
+
+	result := []string{}
+	for _, tc := range p.GetTags() {
+		result = append(result, fmt.Sprintf("%s|%d", tc.Name, tc.Count))
+	}
+	expected := []string{"#home|1", "#release|1", "#work|2", "@office|1"}
+	if strings.Join(result, ",") != strings.Join(expected, ",") {
+		t.Fatalf("result tags %v not equal to expected tags %v", result, expected)
+	}
+}
+
+func TestFilterPriority(t *testing.T) {
+	tests := map[string]struct {
+		text        string
+		minPriority int
+		expected    bool
+	}{
+		"no minimum": {
+			text:        "walk dog",
+			minPriority: 0,
+			expected:    true,
+		},
+		"no priority": {
+			text:        "walk dog",
+			minPriority: 2,
+			expected:    false,
+		},
+		"higher priority": {
+			text:        "(A) walk dog",
+			minPriority: 2,
+			expected:    true,
+		},
+		"equal priority": {
+			text:        "! walk dog",
+			minPriority: 2,
+			expected:    true,
+		},
+		"lower priority": {
+			text:        "(C) walk dog",
+			minPriority: 2,
+			expected:    false,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			tsk, err := newDetectedTask(&sources.RawTask{Date: "Mon", Text: test.text})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := (&Filter{MinPriority: test.minPriority}).Match(tsk)
+			if result != test.expected {
+				t.Fatalf("result match %t not equal to expected match %t", result, test.expected)
+			}
+		})
+	}
+}
@@ -15,6 +15,10 @@ type testTask struct {
 
 func (tt *testTask) DaysFrom(t time.Time) int { return tt.daysFrom }
 
+func (tt *testTask) ID() string { return tt.id }
+
+func (tt *testTask) Source() sources.Source { return sources.Source{} }
+
 func (tt *testTask) String() string { return "" }
 
 func (tt *testTask) equal(other *testTask) bool { return tt.id == other.id }
@@ -0,0 +1,178 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+// severities of lint problems
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// names of lint checks
+const (
+	CheckFile           = "file"
+	CheckSyntax         = "syntax"
+	CheckHeader         = "header"
+	CheckDate           = "date"
+	CheckImpossibleDate = "impossible-date"
+	CheckPastSingle     = "past-single"
+	CheckDuplicate      = "duplicate"
+	CheckReference      = "reference"
+)
+
+// Problem is an issue found in a source file by Lint
+type Problem struct {
+	Path     string
+	Line     int
+	Column   int
+	Severity string
+	Check    string
+	Message  string
+}
+
+func (p *Problem) String() string {
+	pos := p.Path
+	if p.Line > 0 {
+		pos = fmt.Sprintf("%s:%d", pos, p.Line)
+	}
+	if p.Column > 0 {
+		pos = fmt.Sprintf("%s:%d", pos, p.Column)
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", pos, p.Severity, p.Message, p.Check)
+}
+
+// lintEntry is a task found by Lint along with its position
+type lintEntry struct {
+	task Task
+	raw  *sources.RawTask
+	path string
+	line int
+}
+
+// Lint checks the Loader's source files for problems, parsing them exactly as Start does but without
+// sending any tasks for processing. If paths are specified, only those files are checked, with any
+// path not among the Loader's sources checked as a mixed source.
+func (l *Loader) Lint(now time.Time, paths ...string) ([]*Problem, error) {
+	files, err := l.Files(paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []*Problem{}
+	entries := []*lintEntry{}
+	handleErr := func(err error) error {
+		problems = append(problems, loadErrorProblem(err))
+		return nil
+	}
+	rels := newRelatives()
+
+	for _, typ := range sources.Types {
+		for _, fp := range files[typ] {
+			f, err := openSource(fp)
+			if err != nil {
+				_ = handleErr(&LoadError{Path: fp, Err: err})
+				continue
+			}
+			_ = parse(context.Background(), fp, f, newTaskFor(typ), func(t Task, raw *sources.RawTask, line int) {
+				entries = append(entries, &lintEntry{task: t, raw: raw, path: fp, line: line})
+				rels.add(t)
+			}, handleErr)
+		}
+	}
+	_, _ = rels.resolve(handleErr)
+
+	problems = append(problems, lintEntries(entries, now)...)
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Path != problems[j].Path {
+			return problems[i].Path < problems[j].Path
+		}
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return problems, nil
+}
+
+func loadErrorProblem(err error) *Problem {
+	p := &Problem{
+		Severity: SeverityError,
+		Check:    CheckDate,
+		Message:  err.Error(),
+	}
+	loadErr, ok := err.(*LoadError)
+	if !ok {
+		return p
+	}
+
+	p.Path = loadErr.Path
+	p.Line = loadErr.Line
+	p.Column = loadErr.Column
+	p.Message = loadErr.Err.Error()
+	switch {
+	case loadErr.Line == 0:
+		p.Check = CheckFile
+	case loadErr.syntax:
+		p.Check = CheckSyntax
+	case loadErr.header:
+		p.Check = CheckHeader
+	case loadErr.reference:
+		p.Check = CheckReference
+	}
+	return p
+}
+
+// lintEntries checks successfully parsed tasks for dates that do not exist, single tasks in the
+// past, and duplicated tasks
+func lintEntries(entries []*lintEntry, now time.Time) []*Problem {
+	problems := []*Problem{}
+	seen := make(map[string]*lintEntry)
+
+	for _, e := range entries {
+		newProblem := func(severity string, check string, msg string) *Problem {
+			return &Problem{
+				Path:     e.path,
+				Line:     e.line,
+				Column:   e.raw.Column,
+				Severity: severity,
+				Check:    check,
+				Message:  msg,
+			}
+		}
+
+		if v, ok := e.task.(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				problems = append(problems, newProblem(SeverityError, CheckImpossibleDate, err.Error()))
+			}
+		}
+
+		if s, ok := e.task.(*sources.Single); ok && s.DaysFrom(now) < 0 {
+			problems = append(problems, newProblem(SeverityWarning, CheckPastSingle,
+				fmt.Sprintf("single task [%s] on %s is in the past", s, s.Date())))
+		}
+
+		r, ok := e.task.(interface {
+			Type() sources.Type
+			Date() string
+		})
+		if !ok {
+			continue
+		}
+		key := fmt.Sprintf("%s|%s|%s", r.Type(), r.Date(), strings.ToLower(e.task.String()))
+		if first, exists := seen[key]; exists {
+			problems = append(problems, newProblem(SeverityWarning, CheckDuplicate,
+				fmt.Sprintf("task [%s] duplicates the task at %s:%d", e.task, first.path, first.line)))
+			continue
+		}
+		seen[key] = e
+	}
+	return problems
+}
@@ -0,0 +1,139 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	weekly := filepath.Join(dir, "weekly.txt")
+	mixed := filepath.Join(dir, "mixed.txt")
+	missing := filepath.Join(dir, "missing.txt")
+	files := map[string]string{
+		weekly: "Mon: gym\nFunday: rest\n",
+		mixed:  "# comment\nmonday: Gym\nSun clean\nFeb 30: party\nJan 3 2020: dentist\nJan 3 2030: travel\n",
+	}
+	for fp, contents := range files {
+		if err := os.WriteFile(fp, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l := NewLoader(nil, nil)
+	l.AddWeeklySource(weekly, missing)
+	l.AddMixedSource(mixed)
+
+	problems, err := l.Lint(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+
+	type result struct {
+		path  string
+		line  int
+		check string
+	}
+	expected := []result{
+		{path: missing, line: 0, check: CheckFile},
+		{path: mixed, line: 2, check: CheckDuplicate},
+		{path: mixed, line: 3, check: CheckSyntax},
+		{path: mixed, line: 4, check: CheckImpossibleDate},
+		{path: mixed, line: 5, check: CheckPastSingle},
+		{path: weekly, line: 2, check: CheckDate},
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("result number of problems %d not equal to expected number of problems %d: %v", len(problems), len(expected), problems)
+	}
+	for i, p := range problems {
+		r := result{path: p.Path, line: p.Line, check: p.Check}
+		if r != expected[i] {
+			t.Fatalf("result problem %v not equal to expected problem %v", r, expected[i])
+		}
+	}
+}
+
+func TestLintHeader(t *testing.T) {
+	dir := t.TempDir()
+	weekly := filepath.Join(dir, "weekly.txt")
+	err := os.WriteFile(weekly, []byte("#+label: fitness\n#+color: plaid\n#+timezone: Mars/Olympus\nMon: gym\nFunday: rest\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLoader(nil, nil)
+	l.AddWeeklySource(weekly)
+
+	problems, err := l.Lint(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+
+	type result struct {
+		line  int
+		check string
+	}
+	expected := []result{
+		{line: 2, check: CheckHeader},
+		{line: 3, check: CheckHeader},
+		{line: 5, check: CheckDate},
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("result number of problems %d not equal to expected number of problems %d: %v", len(problems), len(expected), problems)
+	}
+	for i, p := range problems {
+		r := result{line: p.Line, check: p.Check}
+		if r != expected[i] {
+			t.Fatalf("result problem %v not equal to expected problem %v", r, expected[i])
+		}
+	}
+}
+
+func TestLintReference(t *testing.T) {
+	dir := t.TempDir()
+	mixed := filepath.Join(dir, "mixed.txt")
+	err := os.WriteFile(mixed, []byte("Mar 3 2030 [label trip]: travel\n1d after trip: unpack\n1d after party: clean up\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLoader(nil, nil)
+	l.AddMixedSource(mixed)
+
+	problems, err := l.Lint(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	if len(problems) != 1 || problems[0].Line != 3 || problems[0].Check != CheckReference {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+}
+
+func TestLintPaths(t *testing.T) {
+	dir := t.TempDir()
+	weekly := filepath.Join(dir, "weekly.txt")
+	other := filepath.Join(dir, "other.txt")
+	files := map[string]string{
+		weekly: "15: not weekly\n",
+		other:  "15: monthly\n",
+	}
+	for fp, contents := range files {
+		if err := os.WriteFile(fp, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l := NewLoader(nil, nil)
+	l.AddWeeklySource(weekly)
+
+	// the configured weekly source keeps its type while other files are checked as mixed sources
+	problems, err := l.Lint(time.Now(), weekly, other)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	if len(problems) != 1 || problems[0].Path != weekly || problems[0].Check != CheckDate {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+}
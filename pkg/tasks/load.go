@@ -1,11 +1,10 @@
 package tasks
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -19,10 +18,13 @@ type Loader struct {
 	ch   chan Task
 	done chan struct{}
 
-	weekly  []string
-	monthly []string
-	annual  []string
-	single  []string
+	sources   map[sources.Type][]string
+	filter    *fileFilter
+	relatives *relatives
+
+	keepGoing bool
+	errLock   sync.Mutex
+	errs      []error
 
 	ctx context.Context
 	eg  *errgroup.Group
@@ -35,33 +37,79 @@ func NewLoader(ch chan Task, done chan struct{}) *Loader {
 		ch:   ch,
 		done: done,
 
-		weekly:  []string{},
-		monthly: []string{},
-		annual:  []string{},
+		sources:   make(map[sources.Type][]string),
+		filter:    &fileFilter{},
+		relatives: newRelatives(),
 
 		ctx: ctx,
 		eg:  eg,
 	}
 }
 
-// AddWeeklySource adds the name of a source file from which weekly tasks are loaded
+// SetFileFilter sets patterns for the files to include and exclude when expanding source
+// directories and globs
+func (l *Loader) SetFileFilter(include []string, exclude []string) {
+	l.filter = &fileFilter{
+		include: include,
+		exclude: exclude,
+	}
+}
+
+// SetKeepGoing sets whether loading continues past errors in source files, in which case the errors
+// are collected and made available from Errors instead of stopping the Loader
+func (l *Loader) SetKeepGoing(keepGoing bool) {
+	l.keepGoing = keepGoing
+}
+
+// Errors returns the errors collected while loading with keep going set, sorted by position
+func (l *Loader) Errors() []error {
+	l.errLock.Lock()
+	defer l.errLock.Unlock()
+
+	errs := append([]error{}, l.errs...)
+	sortErrors(errs)
+	return errs
+}
+
+// handleError returns an error to stop loading or collects it when loading with keep going set
+func (l *Loader) handleError(err error) error {
+	if !l.keepGoing {
+		return err
+	}
+	l.errLock.Lock()
+	defer l.errLock.Unlock()
+	l.errs = append(l.errs, err)
+	return nil
+}
+
+// AddWeeklySource adds the name of a source file, directory, or glob from which weekly tasks are loaded
 func (l *Loader) AddWeeklySource(s ...string) {
-	l.weekly = append(l.weekly, s...)
+	l.addSource(sources.TypeWeekly, s...)
 }
 
-// AddMonthlySource adds the name of a source file from which monthly tasks are loaded
+// AddMonthlySource adds the name of a source file, directory, or glob from which monthly tasks are loaded
 func (l *Loader) AddMonthlySource(s ...string) {
-	l.monthly = append(l.monthly, s...)
+	l.addSource(sources.TypeMonthly, s...)
 }
 
-// AddAnnualSource adds the name of a source file from which annual tasks are loaded
+// AddAnnualSource adds the name of a source file, directory, or glob from which annual tasks are loaded
 func (l *Loader) AddAnnualSource(s ...string) {
-	l.annual = append(l.annual, s...)
+	l.addSource(sources.TypeAnnual, s...)
 }
 
-// AddSingleSource adds the name of a source file from which single tasks are loaded
+// AddSingleSource adds the name of a source file, directory, or glob from which single tasks are loaded
 func (l *Loader) AddSingleSource(s ...string) {
-	l.single = append(l.single, s...)
+	l.addSource(sources.TypeSingle, s...)
+}
+
+// AddMixedSource adds the name of a source file, directory, or glob from which tasks of any type are loaded, with the
+// type of each task detected from its date
+func (l *Loader) AddMixedSource(s ...string) {
+	l.addSource(sources.TypeAuto, s...)
+}
+
+func (l *Loader) addSource(typ sources.Type, s ...string) {
+	l.sources[typ] = append(l.sources[typ], s...)
 }
 
 // Start launches the goroutines that load each task type
@@ -70,58 +118,138 @@ func (l *Loader) Start() error {
 		l.done <- struct{}{}
 	}()
 
-	// start one worker for each type of task (weekly, monthly, annual, single)
-	weeklyCh := make(chan string, len(l.weekly))
-	l.eg.Go(func() error {
-		return l.scan(weeklyCh, newWeeklyTask)
-	})
-	monthlyCh := make(chan string, len(l.monthly))
-	l.eg.Go(func() error {
-		return l.scan(monthlyCh, newMonthlyTask)
-	})
-	annualCh := make(chan string, len(l.annual))
-	l.eg.Go(func() error {
-		return l.scan(annualCh, newAnnualTask)
+	// expand directories and globs before any files are processed
+	expanded, err := l.expand()
+	if err != nil {
+		return err
+	}
+
+	// start one worker for each type of task and send each file on the appropriate channel to be processed
+	for _, typ := range sources.Types {
+		files := expanded[typ]
+		fileCh := make(chan string, len(files))
+		newTask := newTaskFor(typ)
+		l.eg.Go(func() error {
+			return l.scan(fileCh, newTask)
+		})
+		for _, fp := range files {
+			fileCh <- fp
+		}
+		close(fileCh)
+	}
+	err = l.eg.Wait()
+	if err != nil {
+		return err
+	}
+
+	// relative tasks are sent once every task to which they can refer is loaded
+	resolved, err := l.relatives.resolve(l.handleError)
+	for _, t := range resolved {
+		l.ch <- t
+	}
+	return err
+}
+
+// expand expands the directories and globs in the sources of each type
+func (l *Loader) expand() (map[sources.Type][]string, error) {
+	expanded := make(map[sources.Type][]string)
+	for _, typ := range sources.Types {
+		files, err := expandSources(l.sources[typ], l.filter)
+		if err != nil {
+			return expanded, fmt.Errorf("failed to expand %s sources: %v", typ, err)
+		}
+		expanded[typ] = files
+	}
+	return expanded, nil
+}
+
+// Files returns the Loader's source files of each type, with directories and globs expanded. If
+// paths are specified, only those files are returned, with any path not among the Loader's sources
+// returned as a mixed source.
+func (l *Loader) Files(paths ...string) (map[sources.Type][]string, error) {
+	files, err := l.expand()
+	if err != nil || len(paths) == 0 {
+		return files, err
+	}
+	return selectFiles(files, paths)
+}
+
+// Find returns the tasks loaded from the source file line with an identifier, of which there is more
+// than one if the line has multiple dates, skipping any files and lines that cannot be loaded
+func (l *Loader) Find(id string) ([]Task, error) {
+	return l.collect(func(t Task) bool {
+		return t.ID() == id
 	})
-	singleCh := make(chan string, len(l.single))
-	l.eg.Go(func() error {
-		return l.scan(singleCh, newSingleTask)
+}
+
+// Tasks returns all tasks loaded from the Loader's source files, skipping any files and lines that
+// cannot be loaded
+func (l *Loader) Tasks() ([]Task, error) {
+	return l.collect(func(Task) bool {
+		return true
 	})
+}
 
-	// send each file on the appropriate channel to be processed
-	wg := sync.WaitGroup{}
-	wg.Add(4) // wait on the number of goroutines to be launched
-	go func() {
-		defer wg.Done()
-		for _, fp := range l.weekly {
-			weeklyCh <- fp
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		for _, fp := range l.monthly {
-			monthlyCh <- fp
+// collect returns the tasks loaded from the Loader's source files for which keep returns true
+func (l *Loader) collect(keep func(Task) bool) ([]Task, error) {
+	files, err := l.expand()
+	if err != nil {
+		return nil, err
+	}
+
+	found := []Task{}
+	skipErr := func(error) error { return nil }
+	rels := newRelatives()
+	for _, typ := range sources.Types {
+		for _, fp := range files[typ] {
+			f, err := openSource(fp)
+			if err != nil {
+				continue
+			}
+			_ = parse(context.Background(), fp, f, newTaskFor(typ), func(t Task, _ *sources.RawTask, _ int) {
+				if !rels.add(t) && keep(t) {
+					found = append(found, t)
+				}
+			}, skipErr)
 		}
-	}()
-	go func() {
-		defer wg.Done()
-		for _, fp := range l.annual {
-			annualCh <- fp
+	}
+
+	resolved, _ := rels.resolve(skipErr)
+	for _, t := range resolved {
+		if keep(t) {
+			found = append(found, t)
 		}
-	}()
-	go func() {
-		defer wg.Done()
-		for _, fp := range l.single {
-			singleCh <- fp
+	}
+	return found, nil
+}
+
+// selectFiles restricts expanded source files to the specified paths, keeping the type of any path
+// that is a source file and treating all other paths as mixed sources
+func selectFiles(files map[sources.Type][]string, paths []string) (map[sources.Type][]string, error) {
+	types := make(map[string]sources.Type)
+	for _, typ := range sources.Types {
+		for _, fp := range files[typ] {
+			abs, err := filepath.Abs(fp)
+			if err != nil {
+				return nil, err
+			}
+			types[abs] = typ
 		}
-	}()
-	wg.Wait()
+	}
 
-	close(weeklyCh)
-	close(monthlyCh)
-	close(annualCh)
-	close(singleCh)
-	return l.eg.Wait()
+	selected := make(map[sources.Type][]string)
+	for _, fp := range paths {
+		abs, err := filepath.Abs(fp)
+		if err != nil {
+			return nil, err
+		}
+		typ, ok := types[abs]
+		if !ok {
+			typ = sources.TypeAuto
+		}
+		selected[typ] = append(selected[typ], fp)
+	}
+	return selected, nil
 }
 
 type newTaskF func(*sources.RawTask) (Task, error)
@@ -129,11 +257,15 @@ type newTaskF func(*sources.RawTask) (Task, error)
 // scan is a worker that loads the tasks from file names it receives on a channel
 func (l *Loader) scan(fileCh <-chan string, newTask newTaskF) error {
 	for fp := range fileCh {
-		f, err := os.Open(filepath.Clean(fp))
+		f, err := openSource(fp)
 		if err != nil {
-			return err
+			err = l.handleError(&LoadError{Path: fp, Err: err})
+			if err != nil {
+				return err
+			}
+			continue
 		}
-		err = scan(l.ctx, f, newTask, l.ch)
+		err = scan(l.ctx, fp, f, newTask, l.ch, l.relatives, l.handleError)
 		if err != nil {
 			return err
 		}
@@ -141,10 +273,41 @@ func (l *Loader) scan(fileCh <-chan string, newTask newTaskF) error {
 	return nil
 }
 
-func scan(ctx context.Context, r io.ReadCloser, newTask newTaskF, taskCh chan Task) error {
+// scan parses the tasks of a source file, sending each on a channel except for relative tasks, which are
+// held by relatives until every source file is loaded
+func scan(
+	ctx context.Context,
+	fp string,
+	r io.ReadCloser,
+	newTask newTaskF,
+	taskCh chan Task,
+	rels *relatives,
+	handleErr errHandler,
+) error {
+	return parse(ctx, fp, r, newTask, func(t Task, _ *sources.RawTask, _ int) {
+		if !rels.add(t) {
+			taskCh <- t
+		}
+	}, handleErr)
+}
+
+// parse reads the header and then the tasks from a source file, calling emit with each task along with
+// the raw task and line number from which it was constructed
+func parse(
+	ctx context.Context,
+	fp string,
+	r io.ReadCloser,
+	newTask newTaskF,
+	emit func(Task, *sources.RawTask, int),
+	handleErr errHandler,
+) error {
 	defer r.Close() //nolint
 
-	scanner := bufio.NewScanner(r)
+	ids := sources.NewLineIDs(fp)
+	// the header directives before the first task of the file apply to every task in it
+	header := &sources.Header{}
+	inHeader := true
+	scanner := sources.NewScanner(r)
 	for scanner.Scan() {
 		select {
 		case <-ctx.Done():
@@ -153,23 +316,99 @@ func scan(ctx context.Context, r io.ReadCloser, newTask newTaskF, taskCh chan Ta
 		}
 
 		line := scanner.Text()
-		if strings.ReplaceAll(line, " ", "") == "" {
+		if inHeader && sources.IsDirective(line) {
+			err := header.Apply(line)
+			if err != nil {
+				err = handleErr(&LoadError{Path: fp, Line: scanner.Line(), Err: err, header: true})
+				if err != nil {
+					return err
+				}
+			}
 			continue
 		}
+		if strings.TrimSpace(line) == "" || sources.IsComment(line) {
+			continue
+		}
+		if typ, ok := sources.ParseSectionHeader(line); ok {
+			newTask = newTaskFor(typ)
+			continue
+		}
+		inHeader = false
+		id := ids.Next(line)
 		rawTasks, err := sources.ParseLine(line)
 		if err != nil {
-			return fmt.Errorf("failed to load line: %v", err)
+			loadErr := &LoadError{Path: fp, Line: scanner.Line(), Err: fmt.Errorf("failed to load line: %v", err)}
+			var parseErr *sources.ParseError
+			if errors.As(err, &parseErr) {
+				loadErr.Column = parseErr.Column
+				loadErr.Err = fmt.Errorf("failed to load line [%s]: %s", line, parseErr.Msg)
+				loadErr.syntax = true
+			}
+			err = handleErr(loadErr)
+			if err != nil {
+				return err
+			}
+			continue
 		}
 		for _, rawTask := range rawTasks {
+			rawTask.ID = id
+			rawTask.Header = header
+			rawTask.Path = fp
+			rawTask.Line = scanner.Line()
+			rawTask.Raw = line
 			t, err := newTask(rawTask)
 			if err != nil {
-				return fmt.Errorf("failed to parse line: %v", err)
+				err = handleErr(&LoadError{
+					Path:   fp,
+					Line:   scanner.Line(),
+					Column: rawTask.Column,
+					Err:    fmt.Errorf("failed to parse line: %v", err),
+				})
+				if err != nil {
+					return err
+				}
+				continue
 			}
 
-			taskCh <- t
+			emit(t, rawTask, scanner.Line())
 		}
 	}
-	return scanner.Err()
+	err := scanner.Err()
+	if err != nil {
+		return handleErr(&LoadError{Path: fp, Err: err})
+	}
+	return nil
+}
+
+// newTaskFor returns the constructor for tasks of the specified type
+func newTaskFor(typ sources.Type) newTaskF {
+	switch typ {
+	case sources.TypeWeekly:
+		return newWeeklyTask
+	case sources.TypeMonthly:
+		return newMonthlyTask
+	case sources.TypeAnnual:
+		return newAnnualTask
+	case sources.TypeSingle:
+		return newSingleTask
+	case sources.TypeFloating:
+		return newFloatingTask
+	case sources.TypeDeadline:
+		return newDeadlineTask
+	case sources.TypeRelative:
+		return newRelativeTask
+	default:
+		return newDetectedTask
+	}
+}
+
+// newDetectedTask constructs a task of the type detected from its date
+func newDetectedTask(r *sources.RawTask) (Task, error) {
+	typ, err := sources.DetectType(r.Date)
+	if err != nil {
+		return nil, err
+	}
+	return newTaskFor(typ)(r)
 }
 
 func newWeeklyTask(r *sources.RawTask) (Task, error) {
@@ -187,3 +426,15 @@ func newAnnualTask(r *sources.RawTask) (Task, error) {
 func newSingleTask(r *sources.RawTask) (Task, error) {
 	return sources.NewSingle(r)
 }
+
+func newFloatingTask(r *sources.RawTask) (Task, error) {
+	return sources.NewFloating(r)
+}
+
+func newDeadlineTask(r *sources.RawTask) (Task, error) {
+	return sources.NewDeadline(r)
+}
+
+func newRelativeTask(r *sources.RawTask) (Task, error) {
+	return sources.NewRelative(r)
+}
@@ -2,9 +2,16 @@ package tasks
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
 )
 
 func TestScan(t *testing.T) {
@@ -62,7 +69,7 @@ func TestScan(t *testing.T) {
 				testDone <- struct{}{}
 			}()
 
-			err := scan(context.Background(), test.r, newTestTask, resChan)
+			err := scan(context.Background(), "test", test.r, newTestTask, resChan, newRelatives(), failFast)
 
 			// shutdown
 			close(resChan)
@@ -75,3 +82,286 @@ func TestScan(t *testing.T) {
 		})
 	}
 }
+
+func TestScanSectionHeaders(t *testing.T) {
+	r := io.NopCloser(strings.NewReader("Mon: cook\n[monthly]\n15: clean\n[auto]\nMar 3: shop\n2024-03-03: travel"))
+	expected := []string{"*sources.Weekly", "*sources.Monthly", "*sources.Annual", "*sources.Single"}
+
+	resChan := make(chan Task, 100)
+	err := scan(context.Background(), "test", r, newWeeklyTask, resChan, newRelatives(), failFast)
+	close(resChan)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+
+	result := []string{}
+	for res := range resChan {
+		result = append(result, fmt.Sprintf("%T", res))
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("result number of tasks %d not equal to expected number of tasks %d", len(result), len(expected))
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Fatalf("result task type %s not equal to expected task type %s", result[i], expected[i])
+		}
+	}
+}
+
+func TestScanIDs(t *testing.T) {
+	r := io.NopCloser(strings.NewReader("Mon/Wed: cook\n# comment\nTue: clean\nFunday: invalid\nThu: shop"))
+
+	resChan := make(chan Task, 100)
+	err := scan(context.Background(), "test", r, newWeeklyTask, resChan, newRelatives(), func(error) error { return nil })
+	close(resChan)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+
+	// the identifiers of tasks match those of the lines of the file
+	f, err := sources.ReadFile(strings.NewReader("Mon/Wed: cook\n# comment\nTue: clean\nFunday: invalid\nThu: shop"), sources.TypeWeekly)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	expected := map[string]string{
+		"cook":  "Mon/Wed: cook",
+		"clean": "Tue: clean",
+		"shop":  "Thu: shop",
+	}
+
+	numTasks := 0
+	for res := range resChan {
+		numTasks++
+		l := f.Find("test", res.ID())
+		if l == nil {
+			t.Fatalf("result id %s of task [%s] not found in file", res.ID(), res)
+		}
+		if l.Text != expected[res.String()] {
+			t.Fatalf("result line %s not equal to expected line %s", l.Text, expected[res.String()])
+		}
+	}
+	if numTasks != 4 {
+		t.Fatalf("result number of tasks %d not equal to expected number of tasks %d", numTasks, 4)
+	}
+}
+
+func TestScanRelative(t *testing.T) {
+	r := io.NopCloser(strings.NewReader("Mon [label gym]: gym\n1d after gym: stretch\nTue: read"))
+
+	resChan := make(chan Task, 100)
+	rels := newRelatives()
+	err := scan(context.Background(), "test", r, newDetectedTask, resChan, rels, failFast)
+	close(resChan)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+
+	result := []string{}
+	for res := range resChan {
+		result = append(result, res.String())
+	}
+	if strings.Join(result, ",") != "gym,read" {
+		t.Fatalf("result tasks %v not equal to expected tasks %v", result, []string{"gym", "read"})
+	}
+	if len(rels.pending) != 1 || rels.pending[0].String() != "stretch" {
+		t.Fatalf("result pending relative tasks %v not equal to expected relative task [stretch]", rels.pending)
+	}
+	if len(rels.labeled["gym"]) != 1 {
+		t.Fatalf("result number of tasks with label [gym] %d not equal to expected number %d", len(rels.labeled["gym"]), 1)
+	}
+}
+
+func TestScanHeader(t *testing.T) {
+	r := io.NopCloser(strings.NewReader("# work tasks\n#+label: work\n#+tags: office\n\nMon: standup #daily\n#+label: ignored\nTue: deploy"))
+
+	resChan := make(chan Task, 100)
+	err := scan(context.Background(), "test", r, newWeeklyTask, resChan, newRelatives(), failFast)
+	close(resChan)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+
+	expected := map[string]string{
+		"standup #daily": "office,daily",
+		"deploy":         "office",
+	}
+	numTasks := 0
+	for res := range resChan {
+		numTasks++
+		if label := LabelOf(res); label != "work" {
+			t.Fatalf("result label '%s' not equal to expected label '%s'", label, "work")
+		}
+		tags := strings.Join(res.(Tagged).Tags(), ",")
+		if tags != expected[res.String()] {
+			t.Fatalf("result tags %s not equal to expected tags %s", tags, expected[res.String()])
+		}
+	}
+	if numTasks != 2 {
+		t.Fatalf("result number of tasks %d not equal to expected number of tasks %d", numTasks, 2)
+	}
+}
+
+func TestScanSource(t *testing.T) {
+	r := io.NopCloser(strings.NewReader("# weekly\n\nMon: standup\n  Tue : gym  "))
+
+	resChan := make(chan Task, 100)
+	err := scan(context.Background(), "tasks.txt", r, newWeeklyTask, resChan, newRelatives(), failFast)
+	close(resChan)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+
+	expected := map[string]sources.Source{
+		"standup": {Type: sources.TypeWeekly, Path: "tasks.txt", Line: 3, Raw: "Mon: standup"},
+		"gym":     {Type: sources.TypeWeekly, Path: "tasks.txt", Line: 4, Raw: "  Tue : gym  "},
+	}
+	numTasks := 0
+	for res := range resChan {
+		numTasks++
+		if src := res.Source(); src != expected[res.String()] {
+			t.Fatalf("result source %+v not equal to expected source %+v", src, expected[res.String()])
+		}
+	}
+	if numTasks != 2 {
+		t.Fatalf("result number of tasks %d not equal to expected number of tasks %d", numTasks, 2)
+	}
+}
+
+func TestScanError(t *testing.T) {
+	tests := map[string]struct {
+		r        io.ReadCloser
+		expected *LoadError
+	}{
+		"invalid line": {
+			r: io.NopCloser(strings.NewReader("Saturday: cook\n\nMonday clean")),
+			expected: &LoadError{
+				Path:   "test",
+				Line:   3,
+				Column: 13,
+			},
+		},
+		"invalid header directive": {
+			r: io.NopCloser(strings.NewReader("#+label: work\n#+colour: red\nSaturday: cook")),
+			expected: &LoadError{
+				Path: "test",
+				Line: 2,
+			},
+		},
+		"invalid date": {
+			r: io.NopCloser(strings.NewReader("# comment\nSaturday: cook\nMon/ Funday : clean")),
+			expected: &LoadError{
+				Path:   "test",
+				Line:   3,
+				Column: 6,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			resChan := make(chan Task, 100)
+			err := scan(context.Background(), "test", test.r, newWeeklyTask, resChan, newRelatives(), failFast)
+			close(resChan)
+
+			var loadErr *LoadError
+			if !errors.As(err, &loadErr) {
+				t.Fatalf("expected LoadError, got: %v", err)
+			}
+			if loadErr.Path != test.expected.Path || loadErr.Line != test.expected.Line || loadErr.Column != test.expected.Column {
+				t.Fatalf("result error position %s:%d:%d not equal to expected position %s:%d:%d",
+					loadErr.Path, loadErr.Line, loadErr.Column, test.expected.Path, test.expected.Line, test.expected.Column)
+			}
+		})
+	}
+}
+
+func TestLoaderKeepGoing(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "weekly.txt")
+	err := os.WriteFile(fp, []byte("Mon: cook\nFunday: clean\nTue shop\nWed: read\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.txt")
+	unmatched := filepath.Join(dir, "*.tasks")
+
+	taskCh := make(chan Task, 100)
+	done := make(chan struct{}, 1)
+	l := NewLoader(taskCh, done)
+	l.SetKeepGoing(true)
+	l.AddWeeklySource(fp, missing, unmatched)
+
+	err = l.Start()
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	close(taskCh)
+
+	numTasks := 0
+	for range taskCh {
+		numTasks++
+	}
+	if numTasks != 2 {
+		t.Fatalf("result number of tasks %d not equal to expected number of tasks %d", numTasks, 2)
+	}
+
+	errs := l.Errors()
+	expected := []string{
+		missing + ": ",
+		unmatched + ": no files match the glob",
+		fp + ":2:1: ",
+		fp + ":3:9: ",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("result errors %v not equal to expected number of errors %d", errs, len(expected))
+	}
+	sort.Strings(expected)
+	for i, err := range errs {
+		if !strings.HasPrefix(err.Error(), expected[i]) {
+			t.Fatalf("result error '%v' does not start with '%s'", err, expected[i])
+		}
+	}
+}
+
+func TestLoaderFind(t *testing.T) {
+	dir := t.TempDir()
+	weekly := filepath.Join(dir, "weekly.txt")
+	err := os.WriteFile(weekly, []byte("Mon/Wed: cook\nFunday: clean\nTue: shop\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mixed := filepath.Join(dir, "mixed.txt")
+	err = os.WriteFile(mixed, []byte("Mon/Wed: cook\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLoader(nil, nil)
+	l.AddWeeklySource(weekly, filepath.Join(dir, "missing.txt"))
+	l.AddMixedSource(mixed)
+
+	ids := sources.NewLineIDs(weekly)
+	cookID := ids.Next("Mon/Wed: cook")
+
+	found, err := l.Find(cookID)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("result number of tasks %d not equal to expected number of tasks %d", len(found), 2)
+	}
+	for _, tsk := range found {
+		if tsk.ID() != cookID || tsk.String() != "cook" {
+			t.Fatalf("result task [%s] with id %s not equal to expected task [cook] with id %s", tsk, tsk.ID(), cookID)
+		}
+	}
+
+	found, err = l.Find("0000000")
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	if len(found) != 0 {
+		t.Fatalf("result number of tasks %d not equal to expected number of tasks %d", len(found), 0)
+	}
+}
@@ -0,0 +1,117 @@
+package tasks
+
+import (
+	"sort"
+	"time"
+)
+
+// LastOccurrence returns the most recent occurrence of a task displayed on or before a date, searching
+// back at most maxDays days, as an Override from the original date of the occurrence to the date on
+// which it is displayed, which are the same unless the occurrence was moved by an override
+func LastOccurrence(t Task, date time.Time, maxDays int, overrides OverrideStore) (Override, bool) {
+	moved := overridesOf(t, overrides)
+	for day := 0; day <= maxDays; day++ {
+		d := date.AddDate(0, 0, -day)
+		for _, o := range moved {
+			if daysBetween(o.To, d) == 0 {
+				return o, true
+			}
+		}
+		if t.DaysFrom(d) == 0 && !isOverridden(moved, d) {
+			return Override{Date: d, To: d}, true
+		}
+	}
+	return Override{}, false
+}
+
+// NextOccurrences returns the next n occurrences of the tasks of a source file line displayed on or after
+// a date, as Overrides from the original dates of the occurrences to the dates on which they are displayed,
+// ordered by the dates on which they are displayed
+func NextOccurrences(tsks []Task, date time.Time, n int, overrides OverrideStore) []Override {
+	next := []Override{}
+	for _, t := range tsks {
+		moved := overridesOf(t, overrides)
+		// search for an additional occurrence for each that may have been moved
+		m := n + len(moved)
+		for _, d := range occurrences(t, date, m, m*maxDaysBetweenAnyOccurrences, 1) {
+			if !isOverridden(moved, d) {
+				next = append(next, Override{Date: d, To: d})
+			}
+		}
+		for _, o := range moved {
+			if daysBetween(date, o.To) >= 0 {
+				next = append(next, o)
+			}
+		}
+	}
+
+	sort.SliceStable(next, func(i, j int) bool {
+		return daysBetween(next[i].To, next[j].To) > 0
+	})
+	if len(next) > n {
+		next = next[:n]
+	}
+	return next
+}
+
+// occurrences returns up to n dates on which a task occurs, searching at most maxDays days from a date
+// in the direction of step, which is 1 to search forward from the date and -1 to search backward from the
+// day before it. Each day is checked rather than skipping ahead by DaysFrom since a date that does not
+// exist in every year, such as Feb 29, can be skipped by the days until the next occurrence.
+func occurrences(t Task, date time.Time, n int, maxDays int, step int) []time.Time {
+	dates := []time.Time{}
+	start := 0
+	if step < 0 {
+		start = 1
+	}
+	for day := start; day <= maxDays && len(dates) < n; day++ {
+		d := date.AddDate(0, 0, step*day)
+		if t.DaysFrom(d) == 0 {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+// displayedOn reports whether an occurrence of a task is displayed on a date, given the overrides of the
+// task's occurrences
+func displayedOn(t Task, overrides []Override, date time.Time) bool {
+	for _, o := range overrides {
+		if daysBetween(o.To, date) == 0 {
+			return true
+		}
+	}
+	return t.DaysFrom(date) == 0 && !isOverridden(overrides, date)
+}
+
+// overridesOf returns the overrides of the occurrences of a task
+func overridesOf(t Task, overrides OverrideStore) []Override {
+	moved := []Override{}
+	if overrides == nil {
+		return moved
+	}
+	for _, o := range overrides.Overrides(t.ID()) {
+		// the tasks of a line with multiple dates share an identifier but occur on different dates
+		if t.DaysFrom(o.Date) == 0 {
+			moved = append(moved, o)
+		}
+	}
+	return moved
+}
+
+// isOverridden reports whether the occurrence on a date has been moved by an override
+func isOverridden(overrides []Override, date time.Time) bool {
+	for _, o := range overrides {
+		if daysBetween(o.Date, date) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// daysBetween returns the number of calendar days from one date to another
+func daysBetween(from time.Time, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
@@ -0,0 +1,186 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+func TestLastOccurrence(t *testing.T) {
+	// a Wednesday
+	date := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		raw              *sources.RawTask
+		newTask          newTaskF
+		maxDays          int
+		overrides        testOverrides
+		expected         time.Time
+		expectedOriginal time.Time
+		expectedOk       bool
+	}{
+		"occurs on date": {
+			raw:              &sources.RawTask{Date: "Wed"},
+			newTask:          newWeeklyTask,
+			maxDays:          7,
+			expected:         date,
+			expectedOriginal: date,
+			expectedOk:       true,
+		},
+		"occurred earlier in the week": {
+			raw:              &sources.RawTask{Date: "Mon"},
+			newTask:          newWeeklyTask,
+			maxDays:          7,
+			expected:         time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC),
+			expectedOriginal: time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC),
+			expectedOk:       true,
+		},
+		"occurred last week": {
+			raw:              &sources.RawTask{Date: "Thu"},
+			newTask:          newWeeklyTask,
+			maxDays:          7,
+			expected:         time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC),
+			expectedOriginal: time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC),
+			expectedOk:       true,
+		},
+		"occurred before max days": {
+			raw:        &sources.RawTask{Date: "Thu"},
+			newTask:    newWeeklyTask,
+			maxDays:    3,
+			expectedOk: false,
+		},
+		"occurrence moved to date": {
+			raw:              &sources.RawTask{Date: "Mon", ID: "gym"},
+			newTask:          newWeeklyTask,
+			maxDays:          7,
+			overrides:        testOverrides{"gym": {{Date: time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC), To: date}}},
+			expected:         date,
+			expectedOriginal: time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC),
+			expectedOk:       true,
+		},
+		"occurrence moved after date": {
+			raw:              &sources.RawTask{Date: "Mon", ID: "gym"},
+			newTask:          newWeeklyTask,
+			maxDays:          14,
+			overrides:        testOverrides{"gym": {{Date: time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC), To: time.Date(2024, time.March, 8, 12, 0, 0, 0, time.UTC)}}},
+			expected:         time.Date(2024, time.February, 26, 12, 0, 0, 0, time.UTC),
+			expectedOriginal: time.Date(2024, time.February, 26, 12, 0, 0, 0, time.UTC),
+			expectedOk:       true,
+		},
+		"future single": {
+			raw:        &sources.RawTask{Date: "2024-03-07"},
+			newTask:    newSingleTask,
+			maxDays:    365,
+			expectedOk: false,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			tsk, err := test.newTask(test.raw)
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			result, ok := LastOccurrence(tsk, date, test.maxDays, test.overrides)
+			if ok != test.expectedOk {
+				t.Fatalf("result ok %t not equal to expected ok %t", ok, test.expectedOk)
+			}
+			if !result.To.Equal(test.expected) {
+				t.Fatalf("result date %v not equal to expected date %v", result.To, test.expected)
+			}
+			if !result.Date.Equal(test.expectedOriginal) {
+				t.Fatalf("result original date %v not equal to expected original date %v", result.Date, test.expectedOriginal)
+			}
+		})
+	}
+}
+
+func TestNextOccurrences(t *testing.T) {
+	// a Wednesday
+	date := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time {
+		return time.Date(2024, time.March, d, 12, 0, 0, 0, time.UTC)
+	}
+	format := func(occurrences []Override) string {
+		dates := []string{}
+		for _, o := range occurrences {
+			d := o.To.Format("2006-01-02")
+			if daysBetween(o.Date, o.To) != 0 {
+				d = o.Date.Format("2006-01-02") + ">" + d
+			}
+			dates = append(dates, d)
+		}
+		return strings.Join(dates, ", ")
+	}
+
+	tests := map[string]struct {
+		dates     []string
+		n         int
+		overrides testOverrides
+		expected  string
+	}{
+		"weekly": {
+			dates:     []string{"Wed"},
+			n:         3,
+			overrides: testOverrides{},
+			expected:  "2024-03-06, 2024-03-13, 2024-03-20",
+		},
+		"line with multiple dates": {
+			dates:     []string{"Mon", "Thu"},
+			n:         3,
+			overrides: testOverrides{},
+			expected:  "2024-03-07, 2024-03-11, 2024-03-14",
+		},
+		"single in the past": {
+			dates:     []string{"Mar 1 2024"},
+			n:         3,
+			overrides: testOverrides{},
+			expected:  "",
+		},
+		"annual": {
+			dates:     []string{"Feb 29"},
+			n:         2,
+			overrides: testOverrides{},
+			expected:  "2025-03-01, 2026-03-01",
+		},
+		"occurrence moved later": {
+			dates:     []string{"Wed"},
+			n:         3,
+			overrides: testOverrides{"id": {{Date: day(6), To: day(21)}}},
+			expected:  "2024-03-13, 2024-03-20, 2024-03-06>2024-03-21",
+		},
+		"occurrence moved from before the date": {
+			dates:     []string{"Mon"},
+			n:         2,
+			overrides: testOverrides{"id": {{Date: day(4), To: day(8)}}},
+			expected:  "2024-03-04>2024-03-08, 2024-03-11",
+		},
+		"occurrence moved before the date": {
+			dates:     []string{"Fri"},
+			n:         2,
+			overrides: testOverrides{"id": {{Date: day(8), To: day(5)}}},
+			expected:  "2024-03-15, 2024-03-22",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			tsks := []Task{}
+			for _, d := range test.dates {
+				tsk, err := newDetectedTask(&sources.RawTask{Date: d, Text: "task", ID: "id"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				tsks = append(tsks, tsk)
+			}
+			result := format(NextOccurrences(tsks, date, test.n, test.overrides))
+			if result != test.expected {
+				t.Fatalf("result occurrences [%s] not equal to expected occurrences [%s]", result, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package tasks
+
+import (
+	"sort"
+	"strings"
+)
+
+// Prioritized is implemented by tasks with a priority parsed from their text, from 1 for the highest
+// priority, or zero for a task without a priority
+type Prioritized interface {
+	Priority() int
+}
+
+// PriorityOf returns the priority of a task, or zero for a task without a priority, which for a Reminder
+// or Countdown is the priority of its task
+func PriorityOf(t Task) int {
+	if p, ok := Unwrap(t).(Prioritized); ok {
+		return p.Priority()
+	}
+	return 0
+}
+
+// SortTasks sorts tasks by priority, with tasks without a priority last, and then by text, followed by
+// reminders from the soonest occurrence
+func SortTasks(tsks []Task) {
+	sort.SliceStable(tsks, func(i, j int) bool {
+		ri, iReminder := tsks[i].(*Reminder)
+		rj, jReminder := tsks[j].(*Reminder)
+		if iReminder != jReminder {
+			return jReminder
+		}
+		if iReminder && ri.Days != rj.Days {
+			return ri.Days < rj.Days
+		}
+
+		pi, pj := PriorityOf(tsks[i]), PriorityOf(tsks[j])
+		if pi != pj {
+			return pj == 0 || (pi != 0 && pi < pj)
+		}
+		return strings.ToLower(tsks[i].String()) < strings.ToLower(tsks[j].String())
+	})
+}
@@ -0,0 +1,30 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+func TestSortTasks(t *testing.T) {
+	tsks := []Task{}
+	for _, text := range []string{"walk dog", "(B) pay rent", "Buy milk", "!! call mom", "(Z) read", "(B) file taxes"} {
+		tsk, err := newDetectedTask(&sources.RawTask{Date: "Mon", Text: text})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tsks = append(tsks, tsk)
+	}
+
+	SortTasks(tsks)
+
+	result := []string{}
+	for _, tsk := range tsks {
+		result = append(result, tsk.String())
+	}
+	expected := []string{"!! call mom", "(B) file taxes", "(B) pay rent", "(Z) read", "Buy milk", "walk dog"}
+	if strings.Join(result, ",") != strings.Join(expected, ",") {
+		t.Fatalf("result order %v not equal to expected order %v", result, expected)
+	}
+}
@@ -1,10 +1,47 @@
 package tasks
 
 import (
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
+// CompletionStore reports whether occurrences of tasks have been completed
+type CompletionStore interface {
+	Done(id string, date time.Time) bool
+	// LastDone returns the date of the most recent completed occurrence of a task
+	LastDone(id string) (time.Time, bool)
+	// FirstDone returns the date of the earliest completed occurrence of a task
+	FirstDone(id string) (time.Time, bool)
+}
+
+// floatingTask is implemented by tasks that recur a number of days after they were last completed
+type floatingTask interface {
+	Task
+	SetToday(time.Time)
+	SetLastDone(time.Time)
+}
+
+// Override moves the occurrence of a task on a date to another date
+type Override struct {
+	Date time.Time
+	To   time.Time
+}
+
+// OverrideStore provides the overrides of occurrences of tasks
+type OverrideStore interface {
+	Overrides(id string) []Override
+}
+
+// Overdue is a past occurrence of a task that has not been completed
+type Overdue struct {
+	Task Task
+	Date time.Time
+	// DaysLate is the number of days from the occurrence to today
+	DaysLate int
+}
+
 // Processor groups and filters tasks
 type Processor struct {
 	now     time.Time
@@ -13,9 +50,18 @@ type Processor struct {
 	in   <-chan Task
 	done <-chan struct{}
 
-	wg    *sync.WaitGroup
-	lock  sync.RWMutex
-	tasks map[int][]Task
+	// today, lookback, and store are used to find overdue occurrences, which are not tracked if lookback is zero
+	today    time.Time
+	lookback int
+	store    CompletionStore
+
+	overrides OverrideStore
+	filter    *Filter
+
+	wg      *sync.WaitGroup
+	lock    sync.RWMutex
+	tasks   map[int][]Task
+	overdue []*Overdue
 }
 
 // NewProcessor constructs a Processor
@@ -33,6 +79,26 @@ func NewProcessor(now time.Time, maxDays int, in <-chan Task, done <-chan struct
 	}
 }
 
+// SetOverdue sets the Processor to track past occurrences of tasks up to lookback days before today
+// that have not been completed according to a CompletionStore, which also sets when floating tasks
+// were last completed. Floating and deadline tasks that are past due are overdue regardless of lookback.
+func (p *Processor) SetOverdue(today time.Time, lookback int, store CompletionStore) {
+	p.today = today
+	p.lookback = lookback
+	p.store = store
+}
+
+// SetOverrides sets the Processor to move occurrences of tasks from their dates according to an
+// OverrideStore
+func (p *Processor) SetOverrides(overrides OverrideStore) {
+	p.overrides = overrides
+}
+
+// SetFilter sets the Processor to keep only the tasks selected by a Filter
+func (p *Processor) SetFilter(filter *Filter) {
+	p.filter = filter
+}
+
 // Start launches the Processor goroutine
 func (p *Processor) Start() {
 	p.wg.Add(1)
@@ -66,6 +132,50 @@ func (p *Processor) GetTasks(day int) ([]Task, bool) {
 	return []Task{}, false
 }
 
+// GetOverdue returns the overdue occurrences of tasks, sorted from the most days late
+func (p *Processor) GetOverdue() []*Overdue {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	overdue := append([]*Overdue{}, p.overdue...)
+	sort.SliceStable(overdue, func(i, j int) bool {
+		if overdue[i].DaysLate != overdue[j].DaysLate {
+			return overdue[i].DaysLate > overdue[j].DaysLate
+		}
+		return strings.ToLower(overdue[i].Task.String()) < strings.ToLower(overdue[j].Task.String())
+	})
+	return overdue
+}
+
+// GetTags returns the number of occurrences of tasks with each tag and context among the Processor's days
+// and overdue occurrences, not counting reminders and counting each deadline task once
+func (p *Processor) GetTags() []*TagCount {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	tsks := []Task{}
+	// a deadline task is counted once rather than for each day of its countdown
+	counted := make(map[Task]bool)
+	for day := 0; day <= p.maxDays; day++ {
+		for _, t := range p.tasks[day] {
+			switch w := t.(type) {
+			case *Reminder:
+				continue
+			case *Countdown:
+				if counted[w.Task] {
+					continue
+				}
+				counted[w.Task] = true
+			}
+			tsks = append(tsks, t)
+		}
+	}
+	for _, o := range p.overdue {
+		tsks = append(tsks, o.Task)
+	}
+	return countTags(tsks)
+}
+
 func (p *Processor) drain() {
 	for {
 		select {
@@ -78,11 +188,48 @@ func (p *Processor) drain() {
 }
 
 func (p *Processor) add(t Task) {
+	if p.filter != nil && !p.filter.Match(t) {
+		return
+	}
+
+	if f, ok := t.(floatingTask); ok {
+		// a floating task that has not been completed is due today rather than on the first of the days
+		today := p.today
+		if today.IsZero() {
+			today = p.now
+		}
+		f.SetToday(today)
+		if p.store != nil {
+			if last, done := p.store.LastDone(f.ID()); done {
+				f.SetLastDone(last)
+			}
+		}
+	}
+
+	overrides := overridesOf(t, p.overrides)
+	p.addOverdue(t, overrides)
+	p.addReminders(t, overrides)
+
+	if d, ok := t.(deadlineTask); ok {
+		p.addCountdown(d, overrides)
+		return
+	}
+
+	for _, o := range overrides {
+		if days := daysBetween(p.now, o.To); days >= 0 && days <= p.maxDays {
+			p.addToDay(days, t)
+		}
+	}
+
+	// a past occurrence, such as that of a floating task that is past due, is only tracked as overdue
 	days := t.DaysFrom(p.now)
-	if days > p.maxDays {
+	if days < 0 || days > p.maxDays || isOverridden(overrides, p.now.AddDate(0, 0, days)) {
 		return
 	}
+	p.addToDay(days, t)
+}
 
+func (p *Processor) addToDay(days int, t Task) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	if _, exists := p.tasks[days]; !exists {
@@ -90,3 +237,60 @@ func (p *Processor) add(t Task) {
 	}
 	p.tasks[days] = append(p.tasks[days], t)
 }
+
+func (p *Processor) addOverdue(t Task, overrides []Override) {
+	if p.store == nil {
+		return
+	}
+
+	// a deadline task is overdue from the date on which it is due until it is completed
+	if d, ok := t.(deadlineTask); ok {
+		due := dueDate(d, p.today, overrides)
+		if daysLate := daysBetween(due.To, p.today); daysLate > 0 && !p.isShown(due.To) && !p.store.Done(t.ID(), due.Date) {
+			p.addOverdueOccurrence(&Overdue{Task: t, Date: due.To, DaysLate: daysLate})
+		}
+		return
+	}
+
+	// a floating task remains due until it is completed
+	if _, ok := t.(floatingTask); ok {
+		if daysLate := -t.DaysFrom(p.today); daysLate > 0 && !p.isShown(p.today.AddDate(0, 0, -daysLate)) {
+			p.addOverdueOccurrence(&Overdue{Task: t, Date: p.today.AddDate(0, 0, -daysLate), DaysLate: daysLate})
+		}
+		return
+	}
+
+	if p.lookback == 0 {
+		return
+	}
+
+	for daysLate := 1; daysLate <= p.lookback; daysLate++ {
+		date := p.today.AddDate(0, 0, -daysLate)
+		if t.DaysFrom(date) != 0 || isOverridden(overrides, date) || p.isShown(date) || p.store.Done(t.ID(), date) {
+			continue
+		}
+		p.addOverdueOccurrence(&Overdue{Task: t, Date: date, DaysLate: daysLate})
+	}
+
+	// an occurrence moved to a past date is overdue from that date, and is done if its original date is
+	for _, o := range overrides {
+		daysLate := daysBetween(o.To, p.today)
+		if daysLate < 1 || daysLate > p.lookback || p.isShown(o.To) || p.store.Done(t.ID(), o.Date) {
+			continue
+		}
+		p.addOverdueOccurrence(&Overdue{Task: t, Date: o.To, DaysLate: daysLate})
+	}
+}
+
+// isShown reports whether a date is one of the Processor's days, on which an occurrence is displayed
+// rather than listed as overdue
+func (p *Processor) isShown(date time.Time) bool {
+	days := daysBetween(p.now, date)
+	return days >= 0 && days <= p.maxDays
+}
+
+func (p *Processor) addOverdueOccurrence(o *Overdue) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.overdue = append(p.overdue, o)
+}
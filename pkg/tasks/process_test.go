@@ -1,8 +1,12 @@
 package tasks
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
 )
 
 func TestAdd(t *testing.T) {
@@ -159,3 +163,553 @@ func TestAdd(t *testing.T) {
 		})
 	}
 }
+
+type testStore map[string]bool
+
+func (s testStore) Done(id string, date time.Time) bool {
+	return s[id+"|"+date.Format("2006-01-02")]
+}
+
+func (s testStore) LastDone(id string) (time.Time, bool) {
+	return s.doneDate(id, func(date string, found string) bool { return date > found })
+}
+
+func (s testStore) FirstDone(id string) (time.Time, bool) {
+	return s.doneDate(id, func(date string, found string) bool { return date < found })
+}
+
+func (s testStore) doneDate(id string, prefer func(date string, found string) bool) (time.Time, bool) {
+	found := ""
+	for key, done := range s {
+		parts := strings.SplitN(key, "|", 2)
+		if done && parts[0] == id && (found == "" || prefer(parts[1], found)) {
+			found = parts[1]
+		}
+	}
+	if found == "" {
+		return time.Time{}, false
+	}
+	date, err := time.Parse("2006-01-02", found)
+	return date, err == nil
+}
+
+func TestOverdue(t *testing.T) {
+	// a Wednesday
+	today := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+
+	newTask := func(date string, text string) Task {
+		tsk, err := newDetectedTask(&sources.RawTask{Date: date, Text: text, ID: text})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tsk
+	}
+
+	tests := map[string]struct {
+		back     int
+		lookback int
+		tasks    []Task
+		store    testStore
+		expected []string
+	}{
+		"lookback zero": {
+			lookback: 0,
+			tasks:    []Task{newTask("Mon", "gym")},
+			store:    testStore{},
+			expected: []string{},
+		},
+		"past occurrence": {
+			lookback: 7,
+			tasks:    []Task{newTask("Mon", "gym")},
+			store:    testStore{},
+			expected: []string{"gym|2024-03-04|2"},
+		},
+		"today is not overdue": {
+			lookback: 6,
+			tasks:    []Task{newTask("Wed", "gym")},
+			store:    testStore{},
+			expected: []string{},
+		},
+		"occurrence before lookback": {
+			lookback: 1,
+			tasks:    []Task{newTask("Mon", "gym")},
+			store:    testStore{},
+			expected: []string{},
+		},
+		"completed occurrence": {
+			lookback: 7,
+			tasks:    []Task{newTask("Mon", "gym"), newTask("Tue", "read")},
+			store:    testStore{"gym|2024-03-04": true},
+			expected: []string{"read|2024-03-05|1"},
+		},
+		"multiple occurrences sorted by days late": {
+			lookback: 14,
+			tasks:    []Task{newTask("Mar 5 2024", "trip"), newTask("Mon", "gym"), newTask("1", "rent")},
+			store:    testStore{"gym|2024-03-04": true},
+			expected: []string{"gym|2024-02-26|9", "rent|2024-03-01|5", "trip|2024-03-05|1"},
+		},
+		"occurrence shown in past days is not overdue": {
+			back:     3,
+			lookback: 14,
+			tasks:    []Task{newTask("Mon", "gym")},
+			store:    testStore{},
+			expected: []string{"gym|2024-02-26|9"},
+		},
+		"single occurrence shown in past days is not overdue": {
+			back:     7,
+			lookback: 7,
+			tasks:    []Task{newTask("Mar 5 2024", "trip")},
+			store:    testStore{},
+			expected: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			p := NewProcessor(today.AddDate(0, 0, -test.back), 7+test.back, nil, nil)
+			p.SetOverdue(today, test.lookback, test.store)
+			for _, tsk := range test.tasks {
+				p.add(tsk)
+			}
+
+			result := []string{}
+			for _, o := range p.GetOverdue() {
+				result = append(result, fmt.Sprintf("%s|%s|%d", o.Task, o.Date.Format("2006-01-02"), o.DaysLate))
+			}
+			if strings.Join(result, ",") != strings.Join(test.expected, ",") {
+				t.Fatalf("result overdue %v not equal to expected overdue %v", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestFloating(t *testing.T) {
+	today := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+
+	newTask := func(date string, text string) Task {
+		tsk, err := newDetectedTask(&sources.RawTask{Date: date, Text: text, ID: text})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tsk
+	}
+
+	newReminded := func(date string, text string, lead int) Task {
+		tsk, err := newDetectedTask(&sources.RawTask{Date: date, Text: text, ID: text, Reminders: []int{lead}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tsk
+	}
+
+	tests := map[string]struct {
+		back            int
+		task            Task
+		store           testStore
+		expected        []string
+		expectedOverdue []string
+	}{
+		"no history or anchor is due today": {
+			task:            newTask("every 14d", "mow"),
+			store:           testStore{},
+			expected:        []string{"mow|0"},
+			expectedOverdue: []string{},
+		},
+		"anchor without history": {
+			task:            newTask("every 14d from 2024-03-09", "mow"),
+			store:           testStore{},
+			expected:        []string{"mow|3"},
+			expectedOverdue: []string{},
+		},
+		"due after last completion": {
+			task:            newTask("every 1w from 2024-01-01", "mow"),
+			store:           testStore{"mow|2024-02-20": true, "mow|2024-03-01": true},
+			expected:        []string{"mow|2"},
+			expectedOverdue: []string{},
+		},
+		"due beyond max days": {
+			task:            newTask("every 14d", "mow"),
+			store:           testStore{"mow|2024-03-05": true},
+			expected:        []string{},
+			expectedOverdue: []string{},
+		},
+		"past due anchor is overdue": {
+			task:            newTask("every 14d from 2023-12-01", "mow"),
+			store:           testStore{},
+			expected:        []string{},
+			expectedOverdue: []string{"mow|2023-12-01|96"},
+		},
+		"past due completion is overdue": {
+			task:            newTask("every 3d", "mow"),
+			store:           testStore{"mow|2024-02-28": true, "mow|2024-03-01": false},
+			expected:        []string{},
+			expectedOverdue: []string{"mow|2024-03-02|4"},
+		},
+		"no history or anchor is due today with past days": {
+			back:            3,
+			task:            newReminded("every 14d", "mow", 1),
+			store:           testStore{},
+			expected:        []string{"in 1 day: mow|2", "mow|3"},
+			expectedOverdue: []string{},
+		},
+		"past due shown in past days is not overdue": {
+			back:            5,
+			task:            newTask("every 3d", "mow"),
+			store:           testStore{"mow|2024-02-28": true},
+			expected:        []string{"mow|1"},
+			expectedOverdue: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			p := NewProcessor(today.AddDate(0, 0, -test.back), 7+test.back, nil, nil)
+			p.SetOverdue(today, 0, test.store)
+			p.add(test.task)
+
+			for day := range p.tasks {
+				if day < 0 || day > 7+test.back {
+					t.Fatalf("unexpected tasks added to day %d outside of the days", day)
+				}
+			}
+
+			result := []string{}
+			for day := 0; day <= 7+test.back; day++ {
+				tsks, _ := p.GetTasks(day)
+				for _, tsk := range tsks {
+					result = append(result, fmt.Sprintf("%s|%d", tsk, day))
+				}
+			}
+			if strings.Join(result, ",") != strings.Join(test.expected, ",") {
+				t.Fatalf("result tasks %v not equal to expected tasks %v", result, test.expected)
+			}
+
+			resultOverdue := []string{}
+			for _, o := range p.GetOverdue() {
+				resultOverdue = append(resultOverdue, fmt.Sprintf("%s|%s|%d", o.Task, o.Date.Format("2006-01-02"), o.DaysLate))
+			}
+			if strings.Join(resultOverdue, ",") != strings.Join(test.expectedOverdue, ",") {
+				t.Fatalf("result overdue %v not equal to expected overdue %v", resultOverdue, test.expectedOverdue)
+			}
+		})
+	}
+}
+
+type testOverrides map[string][]Override
+
+func (o testOverrides) Overrides(id string) []Override {
+	return o[id]
+}
+
+func TestOverrides(t *testing.T) {
+	// a Wednesday
+	today := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+	date := func(day int) time.Time {
+		return time.Date(2024, time.March, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	newTask := func(date string, text string) Task {
+		tsk, err := newDetectedTask(&sources.RawTask{Date: date, Text: text, ID: text})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tsk
+	}
+
+	tests := map[string]struct {
+		tasks           []Task
+		overrides       testOverrides
+		store           testStore
+		expected        []string
+		expectedOverdue []string
+	}{
+		"no overrides": {
+			tasks:           []Task{newTask("Sat", "gutters")},
+			overrides:       testOverrides{},
+			store:           testStore{},
+			expected:        []string{"gutters|3"},
+			expectedOverdue: []string{},
+		},
+		"moved to another day": {
+			tasks:           []Task{newTask("Sat", "gutters")},
+			overrides:       testOverrides{"gutters": {{Date: date(9), To: date(10)}}},
+			store:           testStore{},
+			expected:        []string{"gutters|4"},
+			expectedOverdue: []string{},
+		},
+		"moved beyond max days": {
+			tasks:           []Task{newTask("Sat", "gutters")},
+			overrides:       testOverrides{"gutters": {{Date: date(9), To: date(20)}}},
+			store:           testStore{},
+			expected:        []string{},
+			expectedOverdue: []string{},
+		},
+		"past occurrence moved to today": {
+			tasks:           []Task{newTask("Mon", "gym")},
+			overrides:       testOverrides{"gym": {{Date: date(4), To: date(6)}}},
+			store:           testStore{},
+			expected:        []string{"gym|0", "gym|5"},
+			expectedOverdue: []string{},
+		},
+		"occurrence moved to the past is overdue": {
+			tasks:           []Task{newTask("Thu", "read")},
+			overrides:       testOverrides{"read": {{Date: date(7), To: date(5)}}},
+			store:           testStore{},
+			expected:        []string{},
+			expectedOverdue: []string{"read|2024-03-05|1"},
+		},
+		"completed occurrence moved to the past is not overdue": {
+			tasks:           []Task{newTask("Thu", "read")},
+			overrides:       testOverrides{"read": {{Date: date(7), To: date(5)}}},
+			store:           testStore{"read|2024-03-07": true},
+			expected:        []string{},
+			expectedOverdue: []string{},
+		},
+		"overdue occurrence moved is not overdue": {
+			tasks:           []Task{newTask("Mon", "gym")},
+			overrides:       testOverrides{"gym": {{Date: date(4), To: date(8)}}},
+			store:           testStore{},
+			expected:        []string{"gym|2", "gym|5"},
+			expectedOverdue: []string{},
+		},
+		"only the task occurring on the date of a line with multiple dates": {
+			tasks:           []Task{newTask("Sat", "gutters"), newTask("Sun", "gutters")},
+			overrides:       testOverrides{"gutters": {{Date: date(9), To: date(11)}}},
+			store:           testStore{},
+			expected:        []string{"gutters|4", "gutters|5"},
+			expectedOverdue: []string{"gutters|2024-03-03|3"},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			p := NewProcessor(today, 5, nil, nil)
+			p.SetOverdue(today, 3, test.store)
+			p.SetOverrides(test.overrides)
+			for _, tsk := range test.tasks {
+				p.add(tsk)
+			}
+
+			result := []string{}
+			for day := 0; day <= 5; day++ {
+				tsks, _ := p.GetTasks(day)
+				for _, tsk := range tsks {
+					result = append(result, fmt.Sprintf("%s|%d", tsk, day))
+				}
+			}
+			if strings.Join(result, ",") != strings.Join(test.expected, ",") {
+				t.Fatalf("result tasks %v not equal to expected tasks %v", result, test.expected)
+			}
+
+			overdue := []string{}
+			for _, o := range p.GetOverdue() {
+				overdue = append(overdue, fmt.Sprintf("%s|%s|%d", o.Task, o.Date.Format("2006-01-02"), o.DaysLate))
+			}
+			if strings.Join(overdue, ",") != strings.Join(test.expectedOverdue, ",") {
+				t.Fatalf("result overdue %v not equal to expected overdue %v", overdue, test.expectedOverdue)
+			}
+		})
+	}
+}
+
+func TestReminders(t *testing.T) {
+	// a Wednesday
+	today := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+	date := func(day int) time.Time {
+		return time.Date(2024, time.March, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	newTask := func(date string, text string) Task {
+		tsk, err := newDetectedTask(&sources.RawTask{Date: date, Text: text, ID: text})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tsk
+	}
+	newReminded := func(date string, text string, reminders ...int) Task {
+		tsk, err := newDetectedTask(&sources.RawTask{Date: date, Text: text, ID: text, Reminders: reminders})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tsk
+	}
+
+	tests := map[string]struct {
+		tasks     []Task
+		overrides testOverrides
+		expected  []string
+	}{
+		"no reminders": {
+			tasks:     []Task{newTask("Mar 9 2024", "trip")},
+			overrides: testOverrides{},
+			expected:  []string{"trip|3"},
+		},
+		"reminders before occurrence": {
+			tasks:     []Task{newReminded("Mar 9 2024", "trip", 2, 1)},
+			overrides: testOverrides{},
+			expected:  []string{"in 2 days: trip|1", "in 1 day: trip|2", "trip|3"},
+		},
+		"reminder of occurrence beyond max days": {
+			tasks:     []Task{newReminded("Mar 14 2024", "trip", 7)},
+			overrides: testOverrides{},
+			expected:  []string{"in 7 days: trip|1"},
+		},
+		"reminder of past occurrence is not displayed": {
+			tasks:     []Task{newReminded("Mar 5 2024", "trip", 1)},
+			overrides: testOverrides{},
+			expected:  []string{},
+		},
+		"reminders of each occurrence of a recurring task": {
+			tasks:     []Task{newReminded("Sat", "trash", 7, 1)},
+			overrides: testOverrides{},
+			expected:  []string{"in 1 day: trash|2", "trash|3", "in 7 days: trash|3"},
+		},
+		"reminder follows snoozed occurrence": {
+			tasks:     []Task{newReminded("Mar 9 2024", "trip", 1)},
+			overrides: testOverrides{"trip": {{Date: date(9), To: date(10)}}},
+			expected:  []string{"in 1 day: trip|3", "trip|4"},
+		},
+		"reminders sorted after tasks": {
+			tasks:     []Task{newReminded("Mar 10 2024", "trip", 3), newReminded("Mar 8 2024", "party", 1), newTask("Mar 7 2024", "gym")},
+			overrides: testOverrides{},
+			expected:  []string{"gym|1", "in 1 day: party|1", "in 3 days: trip|1", "party|2", "trip|4"},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			p := NewProcessor(today, 5, nil, nil)
+			p.SetOverrides(test.overrides)
+			for _, tsk := range test.tasks {
+				p.add(tsk)
+			}
+
+			result := []string{}
+			for day := 0; day <= 5; day++ {
+				tsks, _ := p.GetTasks(day)
+				SortTasks(tsks)
+				for _, tsk := range tsks {
+					result = append(result, fmt.Sprintf("%s|%d", tsk, day))
+				}
+			}
+			if strings.Join(result, ",") != strings.Join(test.expected, ",") {
+				t.Fatalf("result tasks %v not equal to expected tasks %v", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	today := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+	date := func(day int) time.Time {
+		return time.Date(2024, time.March, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	newTask := func(date string, text string) Task {
+		tsk, err := newDetectedTask(&sources.RawTask{Date: date, Text: text, ID: text})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tsk
+	}
+
+	tests := map[string]struct {
+		back            int
+		task            Task
+		overrides       testOverrides
+		store           testStore
+		expected        []string
+		expectedOverdue []string
+	}{
+		"without warning date": {
+			task:            newTask("due 2024-03-08", "taxes"),
+			overrides:       testOverrides{},
+			store:           testStore{},
+			expected:        []string{"taxes due today|2"},
+			expectedOverdue: []string{},
+		},
+		"countdown from warning date": {
+			task:            newTask("due 2024-03-09 from 2024-03-07", "taxes"),
+			overrides:       testOverrides{},
+			store:           testStore{},
+			expected:        []string{"taxes due in 2 days|1", "taxes due tomorrow|2", "taxes due today|3"},
+			expectedOverdue: []string{},
+		},
+		"countdown from past warning date": {
+			task:            newTask("due 2024-03-07 from 2024-03-01", "taxes"),
+			overrides:       testOverrides{},
+			store:           testStore{},
+			expected:        []string{"taxes due tomorrow|0", "taxes due today|1"},
+			expectedOverdue: []string{},
+		},
+		"countdown to due date beyond max days": {
+			task:            newTask("due 2024-03-20 from 2024-03-10", "taxes"),
+			overrides:       testOverrides{},
+			store:           testStore{},
+			expected:        []string{"taxes due in 10 days|4", "taxes due in 9 days|5"},
+			expectedOverdue: []string{},
+		},
+		"countdown to snoozed due date": {
+			task:            newTask("due 2024-03-08 from 2024-03-07", "taxes"),
+			overrides:       testOverrides{"taxes": {{Date: date(8), To: date(10)}}},
+			store:           testStore{},
+			expected:        []string{"taxes due tomorrow|3", "taxes due today|4"},
+			expectedOverdue: []string{},
+		},
+		"past due is overdue": {
+			task:            newTask("due 2024-02-20 from 2024-02-01", "taxes"),
+			overrides:       testOverrides{},
+			store:           testStore{},
+			expected:        []string{},
+			expectedOverdue: []string{"taxes|2024-02-20|15"},
+		},
+		"past due shown in past days is not overdue": {
+			back:            3,
+			task:            newTask("due 2024-03-04 from 2024-03-03", "taxes"),
+			overrides:       testOverrides{},
+			store:           testStore{},
+			expected:        []string{"taxes due tomorrow|0", "taxes due today|1"},
+			expectedOverdue: []string{},
+		},
+		"completed past due is not overdue": {
+			task:            newTask("due 2024-02-20 from 2024-02-01", "taxes"),
+			overrides:       testOverrides{},
+			store:           testStore{"taxes|2024-02-20": true},
+			expected:        []string{},
+			expectedOverdue: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			p := NewProcessor(today.AddDate(0, 0, -test.back), 5+test.back, nil, nil)
+			p.SetOverdue(today, 0, test.store)
+			p.SetOverrides(test.overrides)
+			p.add(test.task)
+
+			result := []string{}
+			for day := 0; day <= 5+test.back; day++ {
+				tsks, _ := p.GetTasks(day)
+				for _, tsk := range tsks {
+					result = append(result, fmt.Sprintf("%s|%d", tsk, day))
+				}
+			}
+			if strings.Join(result, ",") != strings.Join(test.expected, ",") {
+				t.Fatalf("result tasks %v not equal to expected tasks %v", result, test.expected)
+			}
+
+			overdue := []string{}
+			for _, o := range p.GetOverdue() {
+				overdue = append(overdue, fmt.Sprintf("%s|%s|%d", o.Task, o.Date.Format("2006-01-02"), o.DaysLate))
+			}
+			if strings.Join(overdue, ",") != strings.Join(test.expectedOverdue, ",") {
+				t.Fatalf("result overdue %v not equal to expected overdue %v", overdue, test.expectedOverdue)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+// TaskLabeled is implemented by tasks with a label marker, by which relative tasks refer to them
+type TaskLabeled interface {
+	TaskLabel() string
+}
+
+// relatives holds the tasks that relative tasks refer to by label and the relative tasks themselves,
+// which are resolved once every source file is loaded
+type relatives struct {
+	lock    sync.Mutex
+	labeled map[string][]Task
+	pending []*sources.Relative
+
+	resolved map[*sources.Relative][]Task
+	errs     map[*sources.Relative]error
+	// visiting are the relative tasks being resolved, used to detect cycles
+	visiting []*sources.Relative
+}
+
+func newRelatives() *relatives {
+	return &relatives{
+		labeled: make(map[string][]Task),
+	}
+}
+
+// add records a task loaded from a source file, returning whether it is a relative task that is held
+// until it is resolved
+func (r *relatives) add(t Task) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if l, ok := t.(TaskLabeled); ok && l.TaskLabel() != "" {
+		r.labeled[l.TaskLabel()] = append(r.labeled[l.TaskLabel()], t)
+	}
+	if rel, ok := t.(*sources.Relative); ok {
+		r.pending = append(r.pending, rel)
+		return true
+	}
+	return false
+}
+
+// resolve returns a task for each occurrence of the tasks to which each relative task refers, in the order
+// of their source files and lines, handling an error for each relative task that refers to a label of no
+// task, to a floating task, or to itself through other relative tasks
+func (r *relatives) resolve(handleErr errHandler) ([]Task, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.resolved = make(map[*sources.Relative][]Task)
+	r.errs = make(map[*sources.Relative]error)
+
+	pending := append([]*sources.Relative{}, r.pending...)
+	sort.SliceStable(pending, func(i, j int) bool {
+		si, sj := pending[i].Source(), pending[j].Source()
+		if si.Path != sj.Path {
+			return si.Path < sj.Path
+		}
+		return si.Line < sj.Line
+	})
+
+	tsks := []Task{}
+	for _, rel := range pending {
+		resolved, err := r.resolveOne(rel)
+		if err != nil {
+			src := rel.Source()
+			err = handleErr(&LoadError{
+				Path:      src.Path,
+				Line:      src.Line,
+				Err:       fmt.Errorf("failed to resolve relative task [%s]: %v", rel, err),
+				reference: true,
+			})
+			if err != nil {
+				return tsks, err
+			}
+			continue
+		}
+		tsks = append(tsks, resolved...)
+	}
+	return tsks, nil
+}
+
+// resolveOne resolves a relative task, resolving first any relative tasks to which it refers
+func (r *relatives) resolveOne(rel *sources.Relative) ([]Task, error) {
+	if tsks, ok := r.resolved[rel]; ok {
+		return tsks, nil
+	}
+	if err, ok := r.errs[rel]; ok {
+		return nil, err
+	}
+	for i, v := range r.visiting {
+		if v == rel {
+			return nil, r.cycle(r.visiting[i:])
+		}
+	}
+
+	anchors, ok := r.labeled[rel.Ref()]
+	if !ok {
+		r.errs[rel] = fmt.Errorf("no task has label [%s]", rel.Ref())
+		return nil, r.errs[rel]
+	}
+
+	r.visiting = append(r.visiting, rel)
+	defer func() {
+		r.visiting = r.visiting[:len(r.visiting)-1]
+	}()
+
+	tsks := []Task{}
+	for _, anchor := range anchors {
+		switch a := anchor.(type) {
+		case *sources.Floating:
+			r.errs[rel] = fmt.Errorf("label [%s] is of floating task [%s], which has no fixed dates", rel.Ref(), a)
+			return nil, r.errs[rel]
+		case *sources.Relative:
+			resolved, err := r.resolveOne(a)
+			if err != nil {
+				// a task in a cycle has its own error
+				if _, ok := r.errs[rel]; !ok {
+					r.errs[rel] = fmt.Errorf("label [%s] is of relative task [%s], which cannot be resolved", rel.Ref(), a)
+				}
+				return nil, r.errs[rel]
+			}
+			for _, t := range resolved {
+				tsks = append(tsks, rel.Resolve(t))
+			}
+		default:
+			tsks = append(tsks, rel.Resolve(anchor))
+		}
+	}
+	r.resolved[rel] = tsks
+	return tsks, nil
+}
+
+// cycle records the error of each relative task of a cycle in which each refers to the label of the next
+// and the last to the label of the first
+func (r *relatives) cycle(rels []*sources.Relative) error {
+	labels := []string{}
+	for _, rel := range rels {
+		labels = append(labels, rel.Ref())
+	}
+	labels = append(labels, labels[0])
+	err := fmt.Errorf("labels refer to each other in a cycle [%s]", strings.Join(labels, " -> "))
+	for _, rel := range rels {
+		r.errs[rel] = err
+	}
+	return err
+}
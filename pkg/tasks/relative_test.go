@@ -0,0 +1,187 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoaderRelative(t *testing.T) {
+	// a Wednesday
+	now := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "tasks.txt")
+	lines := []string{
+		"Mar 10/Apr 1 [label batteries]: change batteries",
+		"3d before batteries: buy supplies",
+		"2d after supplies: unpack",
+		"1d after packing [label supplies]: dangling",
+		"1d after b [label a]: cycle one",
+		"1d after a [label b]: cycle two",
+		"every 3d [label mow]: mow",
+		"1d after mow: rake",
+		"Thu [label review]: review",
+		"1w after review [label followup]: follow up",
+		"1d before followup: prepare",
+	}
+	err := os.WriteFile(fp, []byte(strings.Join(lines, "\n")), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	taskCh := make(chan Task, 100)
+	done := make(chan struct{}, 1)
+	l := NewLoader(taskCh, done)
+	l.SetKeepGoing(true)
+	l.AddMixedSource(fp)
+
+	err = l.Start()
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	close(taskCh)
+
+	result := []string{}
+	for tsk := range taskCh {
+		if _, ok := tsk.(interface{ Ref() string }); ok {
+			result = append(result, tsk.String()+"|"+now.AddDate(0, 0, tsk.DaysFrom(now)).Format("2006-01-02"))
+		}
+	}
+	sort.Strings(result)
+	expected := []string{
+		"buy supplies|2024-03-07",
+		"buy supplies|2024-03-29",
+		"follow up|2024-03-07",
+		"prepare|2024-03-06",
+	}
+	if strings.Join(result, ",") != strings.Join(expected, ",") {
+		t.Fatalf("result relative tasks %v not equal to expected relative tasks %v", result, expected)
+	}
+
+	errs := l.Errors()
+	expectedErrs := []string{
+		fp + ":3: failed to resolve relative task [unpack]: label [supplies] is of relative task [dangling], which cannot be resolved",
+		fp + ":4: failed to resolve relative task [dangling]: no task has label [packing]",
+		fp + ":5: failed to resolve relative task [cycle one]: labels refer to each other in a cycle [b -> a -> b]",
+		fp + ":6: failed to resolve relative task [cycle two]: labels refer to each other in a cycle [b -> a -> b]",
+		fp + ":8: failed to resolve relative task [rake]: label [mow] is of floating task [mow], which has no fixed dates",
+	}
+	if len(errs) != len(expectedErrs) {
+		t.Fatalf("result errors %v not equal to expected errors %v", errs, expectedErrs)
+	}
+	for i, err := range errs {
+		if err.Error() != expectedErrs[i] {
+			t.Fatalf("result error '%v' not equal to expected error '%s'", err, expectedErrs[i])
+		}
+	}
+}
+
+func TestLoaderRelativeSources(t *testing.T) {
+	// a Wednesday
+	now := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+
+	dir := t.TempDir()
+	weekly := filepath.Join(dir, "weekly.txt")
+	mixed := filepath.Join(dir, "mixed.txt")
+	err := os.WriteFile(weekly, []byte("Thu [label review]: review\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		lines       string
+		keepGoing   bool
+		expected    []string
+		expectedErr string
+		numErrs     int
+	}{
+		"resolved across files": {
+			lines:    "",
+			expected: []string{"prepare|2024-03-06", "review|2024-03-07"},
+		},
+		"dangling reference fails": {
+			lines:       "1d after missing: dangling\n",
+			expectedErr: mixed + ":2: failed to resolve relative task [dangling]: no task has label [missing]",
+		},
+		"cycle fails": {
+			lines:       "1d after b [label a]: cycle one\n1d after a [label b]: cycle two\n",
+			expectedErr: mixed + ":2: failed to resolve relative task [cycle one]: labels refer to each other in a cycle [b -> a -> b]",
+		},
+		"keep going past errors": {
+			lines:     "1d after missing: dangling\n1d after b [label a]: cycle one\n1d after a [label b]: cycle two\n",
+			keepGoing: true,
+			expected:  []string{"prepare|2024-03-06", "review|2024-03-07"},
+			numErrs:   3,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			err := os.WriteFile(mixed, []byte("1d before review: prepare\n"+test.lines), 0o600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			taskCh := make(chan Task, 100)
+			done := make(chan struct{}, 1)
+			l := NewLoader(taskCh, done)
+			l.SetKeepGoing(test.keepGoing)
+			l.AddWeeklySource(weekly)
+			l.AddMixedSource(mixed)
+
+			err = l.Start()
+			close(taskCh)
+			if test.expectedErr != "" {
+				if err == nil || err.Error() != test.expectedErr {
+					t.Fatalf("result error '%v' not equal to expected error '%s'", err, test.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+
+			result := []string{}
+			for tsk := range taskCh {
+				result = append(result, tsk.String()+"|"+now.AddDate(0, 0, tsk.DaysFrom(now)).Format("2006-01-02"))
+			}
+			sort.Strings(result)
+			if strings.Join(result, ",") != strings.Join(test.expected, ",") {
+				t.Fatalf("result tasks %v not equal to expected tasks %v", result, test.expected)
+			}
+			if len(l.Errors()) != test.numErrs {
+				t.Fatalf("result errors %v not equal to expected number of errors %d", l.Errors(), test.numErrs)
+			}
+		})
+	}
+}
+
+func TestLoaderTasksRelative(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "tasks.txt")
+	err := os.WriteFile(fp, []byte("2d after review: follow up\nMon/Thu [label review]: review\n1d after missing: dangling\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLoader(nil, nil)
+	l.AddMixedSource(fp)
+	tsks, err := l.Tasks()
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+
+	result := []string{}
+	for _, tsk := range tsks {
+		result = append(result, tsk.String())
+	}
+	expected := []string{"review", "review", "follow up", "follow up"}
+	if strings.Join(result, ",") != strings.Join(expected, ",") {
+		t.Fatalf("result tasks %v not equal to expected tasks %v", result, expected)
+	}
+}
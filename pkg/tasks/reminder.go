@@ -0,0 +1,58 @@
+package tasks
+
+import (
+	"fmt"
+	"time"
+)
+
+// Reminded is implemented by tasks that are reminded a number of days before each of their occurrences
+type Reminded interface {
+	Reminders() []int
+}
+
+// Reminder is a reminder of an occurrence of a task, displayed a number of days before the occurrence
+type Reminder struct {
+	Task
+	// Days is the number of days from the reminder to the occurrence
+	Days int
+}
+
+func (r *Reminder) String() string {
+	if r.Days == 1 {
+		return fmt.Sprintf("in 1 day: %s", r.Task)
+	}
+	return fmt.Sprintf("in %d days: %s", r.Days, r.Task)
+}
+
+// Occurrence returns the date of the occurrence of which a reminder displayed on a date reminds
+func (r *Reminder) Occurrence(date time.Time) time.Time {
+	return date.AddDate(0, 0, r.Days)
+}
+
+// addReminders adds a Reminder of a task to each of the Processor's days that is one of the task's lead
+// times before an occurrence of the task, including occurrences after the Processor's days
+func (p *Processor) addReminders(t Task, overrides []Override) {
+	r, ok := t.(Reminded)
+	if !ok {
+		return
+	}
+	for _, lead := range r.Reminders() {
+		for day := 0; day <= p.maxDays; day++ {
+			if displayedOn(t, overrides, p.now.AddDate(0, 0, day+lead)) {
+				p.addToDay(day, &Reminder{Task: t, Days: lead})
+			}
+		}
+	}
+}
+
+// Unwrap returns the task of which a Reminder reminds or that a Countdown counts down to, or otherwise
+// the task itself
+func Unwrap(t Task) Task {
+	switch w := t.(type) {
+	case *Reminder:
+		return w.Task
+	case *Countdown:
+		return w.Task
+	}
+	return t
+}
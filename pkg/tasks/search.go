@@ -0,0 +1,168 @@
+package tasks
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// scores of the characters of a fuzzy match
+const (
+	scoreMatch       = 16
+	scoreConsecutive = 8
+	scoreWordStart   = 12
+	scoreTextStart   = 8
+	scoreSameWord    = 24
+	scoreSubstring   = 32
+	penaltyGap       = 1
+	maxPenaltyGap    = 8
+)
+
+// SearchResult is a source file line whose tasks match a search query
+type SearchResult struct {
+	// Tasks are the tasks loaded from the line, which share its text and identifier
+	Tasks []Task
+	// Score ranks the result, higher for a better match
+	Score int
+}
+
+// Search returns the source file lines of the tasks whose text fuzzily matches every word of a query,
+// ignoring case, ordered from the best match. A word matches a text containing its characters in order,
+// and matches score higher when they are consecutive, start words, are within a single word, or are
+// contained in the text as is.
+func Search(tsks []Task, query string) []*SearchResult {
+	words := strings.Fields(strings.ToLower(query))
+	results := []*SearchResult{}
+	if len(words) == 0 {
+		return results
+	}
+
+	lines := make(map[string]*SearchResult)
+	for _, t := range tsks {
+		if r, ok := lines[t.ID()]; ok {
+			r.Tasks = append(r.Tasks, t)
+			continue
+		}
+
+		text := []rune(strings.ToLower(t.String()))
+		total := 0
+		matched := true
+		for _, word := range words {
+			score, ok := fuzzyScore(text, []rune(word))
+			if !ok {
+				matched = false
+				break
+			}
+			total += score
+		}
+		if !matched {
+			continue
+		}
+		r := &SearchResult{Tasks: []Task{t}, Score: total}
+		lines[t.ID()] = r
+		results = append(results, r)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		a, b := results[i].Tasks[0].String(), results[j].Tasks[0].String()
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+	return results
+}
+
+// fuzzyScore scores the best match of the characters of a pattern in order in a text, both lowercase,
+// reporting whether the text contains them at all
+func fuzzyScore(text []rune, pattern []rune) (int, bool) {
+	if len(pattern) == 0 {
+		return 0, true
+	}
+
+	best := 0
+	found := false
+	// try each start of the match since the first occurrence of the first character is not always the best
+	for start, r := range text {
+		if r != pattern[0] {
+			continue
+		}
+		score, ok := scoreFrom(text, pattern, start)
+		if !ok {
+			// a later start cannot match if this one does not
+			break
+		}
+		if !found || score > best {
+			best = score
+			found = true
+		}
+	}
+	return best, found
+}
+
+// scoreFrom scores the match of the characters of a pattern in order in a text, matching each character
+// at its first occurrence after the previous one starting from an index
+func scoreFrom(text []rune, pattern []rune, start int) (int, bool) {
+	score := 0
+	prev := -1
+	i := start
+	for _, p := range pattern {
+		for i < len(text) && text[i] != p {
+			i++
+		}
+		if i == len(text) {
+			return 0, false
+		}
+
+		score += scoreMatch
+		switch {
+		case i == 0:
+			score += scoreTextStart + scoreWordStart
+		case isWordStart(text, i):
+			score += scoreWordStart
+		}
+		if prev >= 0 {
+			if i == prev+1 {
+				score += scoreConsecutive
+			} else {
+				gap := i - prev - 1
+				if gap > maxPenaltyGap {
+					gap = maxPenaltyGap
+				}
+				score -= gap * penaltyGap
+			}
+		}
+		prev = i
+		i++
+	}
+
+	// a pattern contained in the text as is is the strongest match, followed by an abbreviation of a word
+	if prev-start+1 == len(pattern) {
+		score += scoreSubstring
+	} else if isWord(text[start : prev+1]) {
+		score += scoreSameWord
+	}
+	return score, true
+}
+
+// isWordStart reports whether the character at an index of a text starts a word
+func isWordStart(text []rune, i int) bool {
+	return isAlphanumeric(text[i]) && (i == 0 || !isAlphanumeric(text[i-1]))
+}
+
+// isWord reports whether a text is within a single word
+func isWord(text []rune) bool {
+	for _, r := range text {
+		if !isAlphanumeric(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,136 @@
+package tasks
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+func TestSearch(t *testing.T) {
+	newTasks := func(texts ...string) []Task {
+		tsks := []Task{}
+		for _, text := range texts {
+			tsk, err := newDetectedTask(&sources.RawTask{Date: "Mon", Text: text, ID: text})
+			if err != nil {
+				t.Fatal(err)
+			}
+			tsks = append(tsks, tsk)
+		}
+		return tsks
+	}
+
+	tests := map[string]struct {
+		tasks    []Task
+		query    string
+		expected string
+	}{
+		"empty query": {
+			tasks:    newTasks("board meeting"),
+			query:    " ",
+			expected: "",
+		},
+		"no match": {
+			tasks:    newTasks("board meeting", "dentist"),
+			query:    "xyz",
+			expected: "",
+		},
+		"case insensitive": {
+			tasks:    newTasks("Board Meeting", "dentist"),
+			query:    "BOARD",
+			expected: "Board Meeting",
+		},
+		"characters in order": {
+			tasks:    newTasks("board meeting", "dentist", "bread"),
+			query:    "brdmtg",
+			expected: "board meeting",
+		},
+		"characters out of order do not match": {
+			tasks:    newTasks("board meeting"),
+			query:    "gtm",
+			expected: "",
+		},
+		"every word must match": {
+			tasks:    newTasks("board meeting", "team meeting", "board game night"),
+			query:    "board mtg",
+			expected: "board meeting",
+		},
+		"substring ranks above scattered match": {
+			tasks:    newTasks("pay loan", "plan trip"),
+			query:    "plan",
+			expected: "plan trip,pay loan",
+		},
+		"contained word ranks above scattered match": {
+			tasks:    newTasks("rake the front yard", "pay rent"),
+			query:    "rent",
+			expected: "pay rent,rake the front yard",
+		},
+		"word starts rank above word middles": {
+			tasks:    newTasks("sharpen tools", "pay taxes"),
+			query:    "pt",
+			expected: "pay taxes,sharpen tools",
+		},
+		"abbreviation of a word ranks above match across words": {
+			tasks:    newTasks("bring draft to team meeting", "board meeting"),
+			query:    "brd mtg",
+			expected: "board meeting,bring draft to team meeting",
+		},
+		"ties ranked by shorter text": {
+			tasks:    newTasks("water plants outside", "water plants"),
+			query:    "water",
+			expected: "water plants,water plants outside",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			texts := []string{}
+			for _, r := range Search(test.tasks, test.query) {
+				texts = append(texts, r.Tasks[0].String())
+			}
+			result := strings.Join(texts, ",")
+			if result != test.expected {
+				t.Fatalf("result [%s] not equal to expected [%s]", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestSearchGroupsLines(t *testing.T) {
+	tsks := []Task{}
+	for _, date := range []string{"Mon", "Thu"} {
+		tsk, err := newDetectedTask(&sources.RawTask{Date: date, Text: "gym", ID: "gym"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tsks = append(tsks, tsk)
+	}
+
+	results := Search(tsks, "gym")
+	if len(results) != 1 {
+		t.Fatalf("result number of results %d not equal to expected number of results %d", len(results), 1)
+	}
+	if len(results[0].Tasks) != 2 {
+		t.Fatalf("result number of tasks %d not equal to expected number of tasks %d", len(results[0].Tasks), 2)
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	words := []string{"call", "plumber", "board", "meeting", "pay", "rent", "water", "plants", "dentist", "taxes"}
+	tsks := []Task{}
+	for i := 0; i < 5000; i++ {
+		text := fmt.Sprintf("%s %s %s %d", words[i%len(words)], words[(i/3)%len(words)], words[(i/7)%len(words)], i)
+		tsk, err := newDetectedTask(&sources.RawTask{Date: "Mon", Text: text, ID: fmt.Sprint(i)})
+		if err != nil {
+			b.Fatal(err)
+		}
+		tsks = append(tsks, tsk)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Search(tsks, "brd mtg")
+	}
+}
@@ -11,6 +11,8 @@ import (
 
 // Annual represents an annual task
 type Annual struct {
+	meta
+
 	month time.Month
 	day   int
 	text  string
@@ -25,7 +27,7 @@ func NewAnnual(raw *RawTask) (*Annual, error) {
 
 	month, err := calendar.ParseMonth(dateParts[0])
 	if err != nil {
-		return &Annual{}, fmt.Errorf("invalid annual date [%s]", raw.Date)
+		return &Annual{}, fmt.Errorf("invalid annual date [%s]: %v", raw.Date, err)
 	}
 	day, err := strconv.ParseInt(dateParts[1], 10, 0)
 	if err != nil {
@@ -39,12 +41,14 @@ func NewAnnual(raw *RawTask) (*Annual, error) {
 		month: month,
 		day:   int(day),
 		text:  raw.Text,
+		meta:  newMeta(raw, TypeAnnual),
 	}
 	return a, nil
 }
 
 // DaysFrom calculates the number of days until a task's date
 func (a *Annual) DaysFrom(t time.Time) int {
+	t = a.in(t)
 	// set the task's year as the input year
 	aTime := time.Date(t.Year(), a.month, a.day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
 	tUnix := t.Unix()
@@ -59,6 +63,26 @@ func (a *Annual) DaysFrom(t time.Time) int {
 	return int(days)
 }
 
+// Type returns the type of the task
+func (a *Annual) Type() Type {
+	return TypeAnnual
+}
+
+// Date returns the task's date in canonical form
+func (a *Annual) Date() string {
+	return fmt.Sprintf("%s %d", a.month.String()[:3], a.day)
+}
+
+// Validate returns an error if the task's date does not exist in any year (e.g., Feb 30), which is
+// not rejected by NewAnnual since DaysFrom rolls such a date over into the next month
+func (a *Annual) Validate() error {
+	// use a leap year so that Feb 29 is valid
+	if !calendar.IsValidDate(2024, a.month, a.day) {
+		return fmt.Errorf("date [%s] does not exist", a.Date())
+	}
+	return nil
+}
+
 func (a *Annual) String() string {
 	return a.text
 }
@@ -166,6 +166,36 @@ func TestNewAnnual(t *testing.T) {
 	}
 }
 
+func TestAnnualDate(t *testing.T) {
+	tests := map[string]struct {
+		date     string
+		expected string
+	}{
+		"full month name": {
+			date:     "april 5",
+			expected: "Apr 5",
+		},
+		"abbreviation": {
+			date:     "DEC 25",
+			expected: "Dec 25",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			r, err := NewAnnual(&RawTask{Date: test.date})
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			result := r.Date()
+			if result != test.expected {
+				t.Fatalf("result date '%s' not equal to expected date '%s'", result, test.expected)
+			}
+		})
+	}
+}
+
 func TestNewAnnualError(t *testing.T) {
 	tests := map[string]struct {
 		raw *RawTask
@@ -215,3 +245,37 @@ func TestNewAnnualError(t *testing.T) {
 		})
 	}
 }
+
+func TestAnnualValidate(t *testing.T) {
+	tests := map[string]struct {
+		a     *Annual
+		valid bool
+	}{
+		"valid": {
+			a:     &Annual{month: time.April, day: 30},
+			valid: true,
+		},
+		"leap day": {
+			a:     &Annual{month: time.February, day: 29},
+			valid: true,
+		},
+		"February 30": {
+			a:     &Annual{month: time.February, day: 30},
+			valid: false,
+		},
+		"April 31": {
+			a:     &Annual{month: time.April, day: 31},
+			valid: false,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			err := test.a.Validate()
+			if (err == nil) != test.valid {
+				t.Fatalf("result valid %t not equal to expected valid %t", err == nil, test.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package sources
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/calendar"
+)
+
+// prefix of the dates of deadline tasks
+const deadlinePrefix = "due"
+
+// Deadline represents a task that is displayed every day from a warning date until it is due
+type Deadline struct {
+	meta
+
+	due     *Single
+	warning *Single
+	text    string
+}
+
+// NewDeadline constructs a Deadline from a date of the form "due <date>", optionally followed by
+// "from <date>" to set the date from which the task is displayed before it is due
+func NewDeadline(raw *RawTask) (*Deadline, error) {
+	date := strings.ToLower(raw.Date)
+	if !isDeadlineDate(date) {
+		return &Deadline{}, fmt.Errorf("invalid deadline date [%s]", raw.Date)
+	}
+	date = strings.TrimSpace(strings.TrimPrefix(date, deadlinePrefix))
+
+	warningStr := ""
+	if i := strings.Index(date, " from "); i >= 0 {
+		warningStr = strings.TrimSpace(date[i+len(" from "):])
+		date = strings.TrimSpace(date[:i])
+	}
+
+	due, err := NewSingle(&RawTask{Date: date})
+	if err != nil {
+		return &Deadline{}, fmt.Errorf("invalid deadline date [%s]: %v", raw.Date, err)
+	}
+	d := &Deadline{
+		due:  due,
+		text: raw.Text,
+		meta: newMeta(raw, TypeDeadline),
+	}
+	if warningStr != "" {
+		warning, err := NewSingle(&RawTask{Date: warningStr})
+		if err != nil {
+			return &Deadline{}, fmt.Errorf("invalid deadline date [%s]: %v", raw.Date, err)
+		}
+		if warning.sortKey() > due.sortKey() {
+			return &Deadline{}, fmt.Errorf("invalid deadline date [%s]: warning date [%s] is after due date [%s]",
+				raw.Date, warning.Date(), due.Date())
+		}
+		d.warning = warning
+	}
+	return d, nil
+}
+
+// isDeadlineDate reports whether a date is that of a deadline task
+func isDeadlineDate(date string) bool {
+	fields := strings.Fields(strings.ToLower(date))
+	return len(fields) > 1 && fields[0] == deadlinePrefix
+}
+
+// DaysFrom calculates the number of days until a task is due, which is negative once it is past due
+func (d *Deadline) DaysFrom(t time.Time) int {
+	t = d.in(t)
+	dTime := time.Date(d.due.year, d.due.month, d.due.day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	days := calendar.UnixToDaysFloored(dTime.Unix() - t.Unix())
+	return int(days)
+}
+
+// WarningDays returns the number of days before the due date from which the task is displayed, which
+// is zero for a task without a warning date
+func (d *Deadline) WarningDays() int {
+	if d.warning == nil {
+		return 0
+	}
+	due := time.Date(d.due.year, d.due.month, d.due.day, 0, 0, 0, 0, time.UTC)
+	warning := time.Date(d.warning.year, d.warning.month, d.warning.day, 0, 0, 0, 0, time.UTC)
+	return int(calendar.UnixToDaysFloored(due.Unix() - warning.Unix()))
+}
+
+// Type returns the type of the task
+func (d *Deadline) Type() Type {
+	return TypeDeadline
+}
+
+// Date returns the task's date in canonical form
+func (d *Deadline) Date() string {
+	date := fmt.Sprintf("%s %s", deadlinePrefix, d.due.Date())
+	if d.warning != nil {
+		date = fmt.Sprintf("%s from %s", date, d.warning.Date())
+	}
+	return date
+}
+
+// Validate returns an error if the task's due or warning date does not exist
+func (d *Deadline) Validate() error {
+	if err := d.due.Validate(); err != nil {
+		return err
+	}
+	if d.warning == nil {
+		return nil
+	}
+	return d.warning.Validate()
+}
+
+func (d *Deadline) String() string {
+	return d.text
+}
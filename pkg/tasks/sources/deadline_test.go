@@ -0,0 +1,134 @@
+package sources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeadlineDaysFrom(t *testing.T) {
+	now := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		date     string
+		expected int
+	}{
+		"future": {
+			date:     "due Apr 15 2024",
+			expected: 40,
+		},
+		"today": {
+			date:     "due 2024-03-06 from 2024-03-01",
+			expected: 0,
+		},
+		"past": {
+			date:     "due Mar 1 2024",
+			expected: -5,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			d, err := NewDeadline(&RawTask{Date: test.date})
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			result := d.DaysFrom(now)
+			if result != test.expected {
+				t.Fatalf("result days %d not equal to expected days %d", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDeadline(t *testing.T) {
+	tests := map[string]struct {
+		raw                 *RawTask
+		expectedDate        string
+		expectedWarningDays int
+	}{
+		"due date": {
+			raw: &RawTask{
+				Date: "due Apr 15 2024",
+				Text: "taxes",
+			},
+			expectedDate:        "due Apr 15 2024",
+			expectedWarningDays: 0,
+		},
+		"warning date": {
+			raw: &RawTask{
+				Date: "Due 2024-04-15 From march 15 2024",
+				Text: "taxes",
+			},
+			expectedDate:        "due Apr 15 2024 from Mar 15 2024",
+			expectedWarningDays: 31,
+		},
+		"warning date on due date": {
+			raw: &RawTask{
+				Date: "due Apr 15 2024 from Apr 15 2024",
+				Text: "taxes",
+			},
+			expectedDate:        "due Apr 15 2024 from Apr 15 2024",
+			expectedWarningDays: 0,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result, err := NewDeadline(test.raw)
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if result.text != test.raw.Text {
+				t.Fatalf("result text '%s' not equal to expected text '%s'", result.text, test.raw.Text)
+			}
+			if result.Date() != test.expectedDate {
+				t.Fatalf("result date '%s' not equal to expected date '%s'", result.Date(), test.expectedDate)
+			}
+			if result.WarningDays() != test.expectedWarningDays {
+				t.Fatalf("result warning days %d not equal to expected warning days %d", result.WarningDays(), test.expectedWarningDays)
+			}
+		})
+	}
+}
+
+func TestNewDeadlineError(t *testing.T) {
+	tests := map[string]struct {
+		raw *RawTask
+	}{
+		"empty": {
+			raw: &RawTask{},
+		},
+		"missing due date": {
+			raw: &RawTask{
+				Date: "due",
+			},
+		},
+		"due date without year": {
+			raw: &RawTask{
+				Date: "due Apr 15",
+			},
+		},
+		"invalid warning date": {
+			raw: &RawTask{
+				Date: "due Apr 15 2024 from tomorrow",
+			},
+		},
+		"warning date after due date": {
+			raw: &RawTask{
+				Date: "due Apr 15 2024 from Apr 16 2024",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			_, err := NewDeadline(test.raw)
+			if err == nil {
+				t.Fatal("unexpected nil error")
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package sources
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/calendar"
+)
+
+// Type identifies the kind of schedule a task follows
+type Type string
+
+// supported task types
+const (
+	TypeWeekly  Type = "weekly"
+	TypeMonthly Type = "monthly"
+	TypeAnnual  Type = "annual"
+	TypeSingle  Type = "single"
+
+	// TypeFloating tasks recur a number of days after they were last completed
+	TypeFloating Type = "floating"
+	// TypeDeadline tasks are displayed every day from a warning date until they are due
+	TypeDeadline Type = "deadline"
+	// TypeRelative tasks occur a number of days before or after the tasks with a label
+	TypeRelative Type = "relative"
+
+	// TypeAuto indicates that the type of each task is to be detected from its date
+	TypeAuto Type = "auto"
+)
+
+// Types is the ordered list of task types, ending with TypeAuto for sources of mixed types
+var Types = []Type{TypeWeekly, TypeMonthly, TypeAnnual, TypeSingle, TypeFloating, TypeDeadline, TypeRelative, TypeAuto}
+
+var isoDate = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// DetectType infers the type of a task from the format of its date
+func DetectType(date string) (Type, error) {
+	if isoDate.MatchString(date) {
+		return TypeSingle, nil
+	}
+	if isFloatingDate(date) {
+		return TypeFloating, nil
+	}
+	if isDeadlineDate(date) {
+		return TypeDeadline, nil
+	}
+	if isRelativeDate(date) {
+		return TypeRelative, nil
+	}
+
+	dateParts := strings.Fields(date)
+	switch len(dateParts) {
+	case 1:
+		if _, err := calendar.ParseWeekday(dateParts[0]); err == nil {
+			return TypeWeekly, nil
+		}
+		if _, err := strconv.ParseInt(dateParts[0], 10, 0); err == nil {
+			return TypeMonthly, nil
+		}
+	case 2:
+		if _, err := calendar.ParseMonth(dateParts[0]); err == nil {
+			return TypeAnnual, nil
+		}
+	case 3:
+		if _, err := calendar.ParseMonth(dateParts[0]); err == nil {
+			return TypeSingle, nil
+		}
+	}
+	// report the most likely cause of an undetectable date
+	switch {
+	case len(dateParts) == 1:
+		return "", fmt.Errorf("could not detect task type from date [%s]: unknown weekday or day of the month [%s]", date, dateParts[0])
+	case len(dateParts) == 2 || len(dateParts) == 3:
+		return "", fmt.Errorf("could not detect task type from date [%s]: unknown month [%s]", date, dateParts[0])
+	}
+	return "", fmt.Errorf("could not detect task type from date [%s]", date)
+}
+
+// ParseSectionHeader parses a section header line of the form [<type>] used to set the type of
+// the tasks that follow it, returning false if the line is not a section header
+func ParseSectionHeader(line string) (Type, bool) {
+	line = cleanString(stripComment(line))
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+
+	typ := Type(strings.ToLower(cleanString(line[1 : len(line)-1])))
+	switch typ {
+	case TypeWeekly, TypeMonthly, TypeAnnual, TypeSingle, TypeFloating, TypeDeadline, TypeRelative, TypeAuto:
+		return typ, true
+	}
+	return "", false
+}
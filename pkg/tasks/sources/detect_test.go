@@ -0,0 +1,158 @@
+package sources
+
+import "testing"
+
+func TestDetectType(t *testing.T) {
+	tests := map[string]struct {
+		date     string
+		expected Type
+	}{
+		"weekly abbreviation": {
+			date:     "Mon",
+			expected: TypeWeekly,
+		},
+		"weekly full name": {
+			date:     "wednesday",
+			expected: TypeWeekly,
+		},
+		"monthly": {
+			date:     "15",
+			expected: TypeMonthly,
+		},
+		"annual": {
+			date:     "Mar 3",
+			expected: TypeAnnual,
+		},
+		"annual full month name": {
+			date:     "april 15",
+			expected: TypeAnnual,
+		},
+		"single": {
+			date:     "Mar 3 2024",
+			expected: TypeSingle,
+		},
+		"single iso format": {
+			date:     "2024-03-03",
+			expected: TypeSingle,
+		},
+		"floating": {
+			date:     "every 14d",
+			expected: TypeFloating,
+		},
+		"floating with anchor": {
+			date:     "Every 2 weeks from Mar 3 2024",
+			expected: TypeFloating,
+		},
+		"deadline": {
+			date:     "due Apr 15 2024",
+			expected: TypeDeadline,
+		},
+		"deadline with warning date": {
+			date:     "Due 2024-04-15 from 2024-03-15",
+			expected: TypeDeadline,
+		},
+		"relative": {
+			date:     "3d before batteries",
+			expected: TypeRelative,
+		},
+		"relative in weeks": {
+			date:     "2 weeks After review",
+			expected: TypeRelative,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result, err := DetectType(test.date)
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if result != test.expected {
+				t.Fatalf("result type '%s' not equal to expected type '%s'", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestDetectTypeError(t *testing.T) {
+	tests := map[string]struct {
+		date string
+	}{
+		"empty": {
+			date: "",
+		},
+		"invalid weekday": {
+			date: "funday",
+		},
+		"invalid month": {
+			date: "xxx 3",
+		},
+		"invalid month with year": {
+			date: "xxx 3 2024",
+		},
+		"too many parts": {
+			date: "Mar 3 2024 12",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			_, err := DetectType(test.date)
+			if err == nil {
+				t.Fatal("unexpected nil error")
+			}
+		})
+	}
+}
+
+func TestParseSectionHeader(t *testing.T) {
+	tests := map[string]struct {
+		line     string
+		expected Type
+		ok       bool
+	}{
+		"weekly": {
+			line:     "[weekly]",
+			expected: TypeWeekly,
+			ok:       true,
+		},
+		"floating": {
+			line:     "[floating]",
+			expected: TypeFloating,
+			ok:       true,
+		},
+		"deadline": {
+			line:     "[deadline]",
+			expected: TypeDeadline,
+			ok:       true,
+		},
+		"auto with spaces and capitals": {
+			line:     "  [ Auto ] ",
+			expected: TypeAuto,
+			ok:       true,
+		},
+		"unknown type": {
+			line: "[daily]",
+			ok:   false,
+		},
+		"task line": {
+			line: "Mon: [weekly]",
+			ok:   false,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result, ok := ParseSectionHeader(test.line)
+			if ok != test.ok {
+				t.Fatalf("result ok %t not equal to expected ok %t", ok, test.ok)
+			}
+			if result != test.expected {
+				t.Fatalf("result type '%s' not equal to expected type '%s'", result, test.expected)
+			}
+		})
+	}
+}
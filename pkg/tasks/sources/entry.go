@@ -0,0 +1,68 @@
+package sources
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/calendar"
+)
+
+// Entry is a validated task line to be added to a source file
+type Entry struct {
+	Line *Line
+	Type Type
+
+	// Days is the number of days from the reference date to the next occurrence of the task
+	Days int
+}
+
+// NewEntry parses and validates a task line of the specified type, detecting the type from the dates
+// for TypeAuto. Relative dates such as "next fri" are replaced by the single date they refer to.
+func NewEntry(line string, typ Type, now time.Time) (*Entry, error) {
+	l, err := Tokenize(line)
+	if err != nil {
+		return nil, fmt.Errorf("invalid line [%s]: %w", line, err)
+	}
+
+	e := &Entry{Line: l, Type: typ}
+	for i, date := range l.Dates {
+		if typ == TypeAuto || typ == TypeSingle {
+			if t, ok := calendar.ParseRelativeDate(date, now); ok {
+				date = fmt.Sprintf("%s %d %d", t.Month().String()[:3], t.Day(), t.Year())
+				l.Dates[i] = date
+			}
+		}
+
+		r, err := newRule(typ, &RawTask{Date: date, Text: l.Text})
+		if err != nil {
+			return nil, fmt.Errorf("invalid line [%s]: %w", line, &ParseError{Column: l.DateColumns[i], Msg: err.Error()})
+		}
+		if v, ok := r.(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid line [%s]: %w", line, &ParseError{Column: l.DateColumns[i], Msg: err.Error()})
+			}
+		}
+
+		// all dates of a line must be of the same type to be added to a single section of a file
+		if i == 0 {
+			e.Type = r.Type()
+			e.Days = r.DaysFrom(now)
+			continue
+		}
+		if r.Type() != e.Type {
+			return nil, fmt.Errorf("invalid line [%s]: %w", line, &ParseError{
+				Column: l.DateColumns[i],
+				Msg:    fmt.Sprintf("date [%s] is %s but the first date of the line is %s", date, r.Type(), e.Type),
+			})
+		}
+		if days := r.DaysFrom(now); days < e.Days {
+			e.Days = days
+		}
+	}
+	return e, nil
+}
+
+// String returns the entry as a line of a source file
+func (e *Entry) String() string {
+	return FormatLine(e.Line)
+}
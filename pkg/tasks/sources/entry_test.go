@@ -0,0 +1,113 @@
+package sources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewEntry(t *testing.T) {
+	// a Wednesday
+	now := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		line         string
+		typ          Type
+		expected     string
+		expectedType Type
+		expectedDays int
+		shouldErr    bool
+	}{
+		"weekly": {
+			line:         "Tue: yoga",
+			typ:          TypeWeekly,
+			expected:     "Tue: yoga",
+			expectedType: TypeWeekly,
+			expectedDays: 6,
+		},
+		"detected monthly": {
+			line:         "15:pay rent  # note",
+			typ:          TypeAuto,
+			expected:     "15: pay rent # note",
+			expectedType: TypeMonthly,
+			expectedDays: 9,
+		},
+		"multiple dates use the next occurrence": {
+			line:         "Mon/Thu: gym",
+			typ:          TypeAuto,
+			expected:     "Mon/Thu: gym",
+			expectedType: TypeWeekly,
+			expectedDays: 1,
+		},
+		"relative date": {
+			line:         "next fri: call plumber",
+			typ:          TypeAuto,
+			expected:     "Mar 8 2024: call plumber",
+			expectedType: TypeSingle,
+			expectedDays: 2,
+		},
+		"relative date for single": {
+			line:         "tomorrow: call plumber",
+			typ:          TypeSingle,
+			expected:     "Mar 7 2024: call plumber",
+			expectedType: TypeSingle,
+			expectedDays: 1,
+		},
+		"past single": {
+			line:         "2024-03-01: past",
+			typ:          TypeAuto,
+			expected:     "2024-03-01: past",
+			expectedType: TypeSingle,
+			expectedDays: -5,
+		},
+		"relative date for weekly": {
+			line:      "next fri: call plumber",
+			typ:       TypeWeekly,
+			shouldErr: true,
+		},
+		"wrong type": {
+			line:      "15: pay rent",
+			typ:       TypeWeekly,
+			shouldErr: true,
+		},
+		"impossible date": {
+			line:      "Feb 30: party",
+			typ:       TypeAuto,
+			shouldErr: true,
+		},
+		"mixed types": {
+			line:      "Mon/15: gym",
+			typ:       TypeAuto,
+			shouldErr: true,
+		},
+		"invalid line": {
+			line:      "Mon gym",
+			typ:       TypeAuto,
+			shouldErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			e, err := NewEntry(test.line, test.typ, now)
+			if test.shouldErr {
+				if err == nil {
+					t.Fatal("expected non-nil error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e.String() != test.expected {
+				t.Fatalf("result %q not equal to expected %q", e.String(), test.expected)
+			}
+			if e.Type != test.expectedType {
+				t.Fatalf("result type %s not equal to expected type %s", e.Type, test.expectedType)
+			}
+			if e.Days != test.expectedDays {
+				t.Fatalf("result days %d not equal to expected days %d", e.Days, test.expectedDays)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package sources
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/calendar"
+)
+
+// format for dates in explanations
+const explainTimeFormat = "Jan 2 2006"
+
+// Rule describes the dates on which the task occurs
+func (w *Weekly) Rule() string {
+	return fmt.Sprintf("every %s", w.day)
+}
+
+// Rule describes the dates on which the task occurs
+func (m *Monthly) Rule() string {
+	return fmt.Sprintf("on the %s of every month", ordinal(m.day))
+}
+
+// Rule describes the dates on which the task occurs
+func (a *Annual) Rule() string {
+	return fmt.Sprintf("on %s %d of every year", a.month, a.day)
+}
+
+// Rule describes the dates on which the task occurs
+func (s *Single) Rule() string {
+	return fmt.Sprintf("once, on %s %d %d", s.month, s.day, s.year)
+}
+
+// Rule describes the dates on which the task occurs
+func (f *Floating) Rule() string {
+	rule := fmt.Sprintf("every %s after it was last done", pluralize(f.interval, "day"))
+	if f.weeks {
+		rule = fmt.Sprintf("every %s after it was last done", pluralize(f.interval/7, "week"))
+	}
+	if f.anchor != nil {
+		rule = fmt.Sprintf("%s, first due on %s", rule, f.anchor.Date())
+	}
+	return rule
+}
+
+// Rule describes the dates on which the task occurs
+func (d *Deadline) Rule() string {
+	rule := fmt.Sprintf("due on %s %d %d", d.due.month, d.due.day, d.due.year)
+	if d.warning != nil {
+		rule = fmt.Sprintf("%s, counting down every day from %s", rule, d.warning.Date())
+	}
+	return rule
+}
+
+// Rule describes the dates on which the task occurs
+func (r *Relative) Rule() string {
+	placement := relativeAfter
+	if r.before {
+		placement = relativeBefore
+	}
+	return fmt.Sprintf("%s %s each occurrence of the tasks labeled [%s]", pluralize(r.interval, "day"), placement, r.ref)
+}
+
+// Adjustment describes why the task occurs on a date other than the one set by its rule, which is
+// empty for every occurrence of a weekly task
+func (w *Weekly) Adjustment(time.Time) string {
+	return ""
+}
+
+// Adjustment describes why the task occurs on a date other than the day of the month set by its rule,
+// which happens when the day does not exist in the previous month and rolls over into the date's month
+func (m *Monthly) Adjustment(date time.Time) string {
+	if date.Day() == m.day {
+		return ""
+	}
+	prev := time.Date(date.Year(), date.Month()-1, 1, 0, 0, 0, 0, time.UTC)
+	return fmt.Sprintf("%s %d has %d days%s, so the %s rolls over %s into %s",
+		prev.Month(), prev.Year(), calendar.DaysInMonth(prev), leapYearNote(prev), ordinal(m.day),
+		pluralize(date.Day(), "day"), date.Month())
+}
+
+// Adjustment describes why the task occurs on a date other than the one set by its rule, which happens
+// when the day does not exist in the month of the date's year, such as Feb 29 in a year that is not a
+// leap year, and rolls over into the next month
+func (a *Annual) Adjustment(date time.Time) string {
+	return rollover(date.Year(), a.month, a.day, date)
+}
+
+// Adjustment describes why the task occurs on a date other than the one set by its rule, which happens
+// when the date does not exist and rolls over into the next month
+func (s *Single) Adjustment(date time.Time) string {
+	return rollover(s.year, s.month, s.day, date)
+}
+
+// Adjustment describes how the date on which the task is due was calculated
+func (f *Floating) Adjustment(time.Time) string {
+	switch {
+	case !f.lastDone.IsZero():
+		return fmt.Sprintf("due %s after it was last done on %s",
+			pluralize(f.interval, "day"), f.lastDone.Format(explainTimeFormat))
+	case f.anchor != nil:
+		return "due on its first due date since it has not been done"
+	}
+	return "due every day until it is first done since it has neither been done nor has a first due date"
+}
+
+// Adjustment describes why the task is due on a date other than the one set by its rule, which happens
+// when the due date does not exist and rolls over into the next month
+func (d *Deadline) Adjustment(date time.Time) string {
+	return rollover(d.due.year, d.due.month, d.due.day, date)
+}
+
+// Adjustment describes why the task occurs on a date other than the one set by its rule, which is
+// empty for every occurrence of a relative task since any adjustment is that of the task to which it refers
+func (r *Relative) Adjustment(time.Time) string {
+	return ""
+}
+
+// rollover describes a day that does not exist in a month of a year rolling over into the next month,
+// which is empty if the day exists
+func rollover(year int, month time.Month, day int, date time.Time) string {
+	if calendar.IsValidDate(year, month, day) {
+		return ""
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return fmt.Sprintf("%s %d has %d days%s, so %s %d rolls over to %s",
+		month, year, calendar.DaysInMonth(first), leapYearNote(first), month.String()[:3], day,
+		date.Format(explainTimeFormat))
+}
+
+// leapYearNote notes whether the year of a date in February is a leap year, which is empty for other months
+func leapYearNote(t time.Time) string {
+	if t.Month() != time.February {
+		return ""
+	}
+	if calendar.DaysInMonth(t) == 29 {
+		return fmt.Sprintf(" (%d is a leap year)", t.Year())
+	}
+	return fmt.Sprintf(" (%d is not a leap year)", t.Year())
+}
+
+// ordinal returns the ordinal form of a day of the month (e.g., 1st, 22nd, 31st)
+func ordinal(day int) string {
+	suffix := "th"
+	switch day % 10 {
+	case 1:
+		suffix = "st"
+	case 2:
+		suffix = "nd"
+	case 3:
+		suffix = "rd"
+	}
+	if day%100 >= 11 && day%100 <= 13 {
+		suffix = "th"
+	}
+	return fmt.Sprintf("%d%s", day, suffix)
+}
+
+// pluralize returns a count followed by a unit that is pluralized unless the count is one
+func pluralize(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
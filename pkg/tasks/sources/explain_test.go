@@ -0,0 +1,85 @@
+package sources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdjustment(t *testing.T) {
+	tests := map[string]struct {
+		r        interface{ Adjustment(time.Time) string }
+		date     time.Time
+		expected string
+	}{
+		"weekly": {
+			r:        &Weekly{day: time.Sunday},
+			date:     time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC),
+			expected: "",
+		},
+		"monthly on its day": {
+			r:        &Monthly{day: 31},
+			date:     time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC),
+			expected: "",
+		},
+		"monthly rolled over from February in a leap year": {
+			r:        &Monthly{day: 31},
+			date:     time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC),
+			expected: "February 2024 has 29 days (2024 is a leap year), so the 31st rolls over 2 days into March",
+		},
+		"monthly rolled over from February in a year that is not a leap year": {
+			r:        &Monthly{day: 31},
+			date:     time.Date(2023, time.March, 3, 0, 0, 0, 0, time.UTC),
+			expected: "February 2023 has 28 days (2023 is not a leap year), so the 31st rolls over 3 days into March",
+		},
+		"monthly rolled over from a month with 30 days": {
+			r:        &Monthly{day: 31},
+			date:     time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC),
+			expected: "April 2024 has 30 days, so the 31st rolls over 1 day into May",
+		},
+		"monthly rolled over by one day": {
+			r:        &Monthly{day: 30},
+			date:     time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			expected: "February 2024 has 29 days (2024 is a leap year), so the 30th rolls over 1 day into March",
+		},
+		"annual on its day": {
+			r:        &Annual{month: time.February, day: 29},
+			date:     time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+			expected: "",
+		},
+		"annual rolled over": {
+			r:        &Annual{month: time.February, day: 29},
+			date:     time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+			expected: "February 2023 has 28 days (2023 is not a leap year), so Feb 29 rolls over to Mar 1 2023",
+		},
+		"single rolled over": {
+			r:        &Single{month: time.April, day: 31, year: 2024},
+			date:     time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC),
+			expected: "April 2024 has 30 days, so Apr 31 rolls over to May 1 2024",
+		},
+		"floating last done": {
+			r:        &Floating{interval: 14, lastDone: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+			date:     time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC),
+			expected: "due 14 days after it was last done on Mar 1 2024",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result := test.r.Adjustment(test.date)
+			if result != test.expected {
+				t.Fatalf("result adjustment '%s' not equal to expected adjustment '%s'", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	tests := map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd", 23: "23rd", 31: "31st"}
+
+	for day, expected := range tests {
+		if result := ordinal(day); result != expected {
+			t.Fatalf("result ordinal %s not equal to expected ordinal %s", result, expected)
+		}
+	}
+}
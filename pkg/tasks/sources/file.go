@@ -0,0 +1,150 @@
+package sources
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// File is a source file read as logical lines that can be modified and written back with all other
+// lines unchanged
+type File struct {
+	Lines []*FileLine
+
+	// typ is the type of the tasks in the file before any section header
+	typ Type
+	// trailingNewline records whether the file ends with a newline
+	trailingNewline bool
+}
+
+// FileLine is a logical line of a File
+type FileLine struct {
+	// Number is the (1-indexed) line number at which the logical line starts
+	Number int
+	// Text is the logical line, with continued lines joined
+	Text string
+	// Type is the type of the section of the file containing the line
+	Type Type
+
+	raw     []string
+	changed bool
+}
+
+// ReadFile reads a source file of the specified type
+func ReadFile(r io.Reader, typ Type) (*File, error) {
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{
+		Lines:           []*FileLine{},
+		typ:             typ,
+		trailingNewline: len(contents) == 0 || bytes.HasSuffix(contents, []byte("\n")),
+	}
+	scanner := NewScanner(bytes.NewReader(contents))
+	lineTyp := typ
+	for scanner.Scan() {
+		line := scanner.Text()
+		if sectionTyp, ok := ParseSectionHeader(line); ok {
+			lineTyp = sectionTyp
+		}
+		f.Lines = append(f.Lines, &FileLine{
+			Number: scanner.Line(),
+			Text:   line,
+			Type:   lineTyp,
+			raw:    scanner.Raw(),
+		})
+	}
+	return f, scanner.Err()
+}
+
+// IsTask reports whether the line is a task line, as opposed to a blank, comment, or header line
+func (l *FileLine) IsTask() bool {
+	if strings.TrimSpace(l.Text) == "" || IsComment(l.Text) {
+		return false
+	}
+	_, isHeader := ParseSectionHeader(l.Text)
+	return !isHeader
+}
+
+// Replace replaces the line's text
+func (l *FileLine) Replace(text string) {
+	l.Text = text
+	l.raw = []string{text}
+	l.changed = true
+}
+
+// Remove removes the line from its File
+func (l *FileLine) Remove() {
+	l.raw = []string{}
+	l.changed = true
+}
+
+// Removed reports whether the line has been removed
+func (l *FileLine) Removed() bool {
+	return l.changed && len(l.raw) == 0
+}
+
+// Find returns the task line of the File, read from the specified path, with an identifier, or nil
+// if there is no such line
+func (f *File) Find(path string, id string) *FileLine {
+	ids := NewLineIDs(path)
+	for _, l := range f.Lines {
+		if !l.IsTask() || l.Removed() {
+			continue
+		}
+		if ids.Next(l.Text) == id {
+			return l
+		}
+	}
+	return nil
+}
+
+// Append adds a task line of the specified type to the end of the File, preceded by a section header
+// if the line would otherwise be in a section of a different type
+func (f *File) Append(text string, typ Type) {
+	endTyp := f.typ
+	number := 1
+	for _, l := range f.Lines {
+		endTyp = l.Type
+		number = l.Number + len(l.raw)
+	}
+
+	if endTyp != typ && endTyp != TypeAuto {
+		header := &FileLine{Number: number, Type: typ}
+		header.Replace("[" + string(typ) + "]")
+		f.Lines = append(f.Lines, header)
+		number++
+	}
+	l := &FileLine{Number: number, Type: typ}
+	l.Replace(text)
+	f.Lines = append(f.Lines, l)
+	f.trailingNewline = true
+}
+
+// Changed reports whether any line of the File has been replaced or removed
+func (f *File) Changed() bool {
+	for _, l := range f.Lines {
+		if l.changed {
+			return true
+		}
+	}
+	return false
+}
+
+// Bytes returns the contents of the File
+func (f *File) Bytes() []byte {
+	lines := []string{}
+	for _, l := range f.Lines {
+		lines = append(lines, l.raw...)
+	}
+	if len(lines) == 0 {
+		return []byte{}
+	}
+	contents := strings.Join(lines, "\n")
+	if f.trailingNewline {
+		contents += "\n"
+	}
+	return []byte(contents)
+}
@@ -0,0 +1,212 @@
+package sources
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadFile(t *testing.T) {
+	tests := map[string]struct {
+		input string
+	}{
+		"empty": {
+			input: "",
+		},
+		"trailing newline": {
+			input: "Mon: a\n\n# comment\nTue: b\n",
+		},
+		"no trailing newline": {
+			input: "Mon: a\nTue: b",
+		},
+		"continued lines and sections": {
+			input: "Mon: a \\\n   continued\n[single]\n  2024-01-01: b  # note\n",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			f, err := ReadFile(strings.NewReader(test.input), TypeWeekly)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if f.Changed() {
+				t.Fatal("unread file reported as changed")
+			}
+			if string(f.Bytes()) != test.input {
+				t.Fatalf("result %q not equal to expected %q", string(f.Bytes()), test.input)
+			}
+		})
+	}
+}
+
+func TestFileLineTypes(t *testing.T) {
+	input := "Mon: a\n[single]\n2024-01-01: b\n[auto]\n15: c\n"
+	expected := []Type{TypeWeekly, TypeSingle, TypeSingle, TypeAuto, TypeAuto}
+
+	f, err := ReadFile(strings.NewReader(input), TypeWeekly)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(f.Lines) != len(expected) {
+		t.Fatalf("result %d not equal to expected %d lines", len(f.Lines), len(expected))
+	}
+	for i, l := range f.Lines {
+		if l.Type != expected[i] {
+			t.Fatalf("result %s not equal to expected %s for line %d", l.Type, expected[i], l.Number)
+		}
+	}
+}
+
+func TestFileModify(t *testing.T) {
+	input := "# tasks\nMon: a \\\n  continued\nTue: b\nWed: c\n"
+
+	f, err := ReadFile(strings.NewReader(input), TypeWeekly)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.Lines[0].IsTask() {
+		t.Fatal("comment line reported as task")
+	}
+	if !f.Lines[1].IsTask() {
+		t.Fatal("task line not reported as task")
+	}
+	if f.Lines[2].Number != 4 {
+		t.Fatalf("result %d not equal to expected line number %d", f.Lines[2].Number, 4)
+	}
+
+	f.Lines[1].Remove()
+	f.Lines[2].Replace("Tue: B")
+	if !f.Changed() {
+		t.Fatal("modified file not reported as changed")
+	}
+	if !f.Lines[1].Removed() || f.Lines[2].Removed() {
+		t.Fatal("result of Removed not equal to expected")
+	}
+
+	expected := "# tasks\nTue: B\nWed: c\n"
+	if string(f.Bytes()) != expected {
+		t.Fatalf("result %q not equal to expected %q", string(f.Bytes()), expected)
+	}
+}
+
+func TestFileAppend(t *testing.T) {
+	tests := map[string]struct {
+		fileTyp  Type
+		input    string
+		typ      Type
+		text     string
+		expected string
+	}{
+		"empty file": {
+			fileTyp:  TypeWeekly,
+			input:    "",
+			typ:      TypeWeekly,
+			text:     "Tue: yoga",
+			expected: "Tue: yoga\n",
+		},
+		"no trailing newline": {
+			fileTyp:  TypeWeekly,
+			input:    "Mon: a",
+			typ:      TypeWeekly,
+			text:     "Tue: yoga",
+			expected: "Mon: a\nTue: yoga\n",
+		},
+		"mixed file": {
+			fileTyp:  TypeAuto,
+			input:    "Mon: a\n",
+			typ:      TypeSingle,
+			text:     "Mar 3 2024: b",
+			expected: "Mon: a\nMar 3 2024: b\n",
+		},
+		"section of same type": {
+			fileTyp:  TypeAuto,
+			input:    "Mon: a\n[single]\nMar 1 2024: b\n",
+			typ:      TypeSingle,
+			text:     "Mar 3 2024: c",
+			expected: "Mon: a\n[single]\nMar 1 2024: b\nMar 3 2024: c\n",
+		},
+		"section of different type": {
+			fileTyp:  TypeAuto,
+			input:    "[weekly]\nMon: a\n",
+			typ:      TypeSingle,
+			text:     "Mar 3 2024: b",
+			expected: "[weekly]\nMon: a\n[single]\nMar 3 2024: b\n",
+		},
+		"file of different type": {
+			fileTyp:  TypeWeekly,
+			input:    "Mon: a\n",
+			typ:      TypeMonthly,
+			text:     "15: b",
+			expected: "Mon: a\n[monthly]\n15: b\n",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			f, err := ReadFile(strings.NewReader(test.input), test.fileTyp)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			f.Append(test.text, test.typ)
+			if string(f.Bytes()) != test.expected {
+				t.Fatalf("result %q not equal to expected %q", string(f.Bytes()), test.expected)
+			}
+		})
+	}
+}
+
+func TestFileFind(t *testing.T) {
+	input := "# Mon: comment\nMon: gym\n[single]\nMon: gym\nMar 3 2024: trip\n"
+
+	ids := NewLineIDs("tasks.txt")
+	gym1 := ids.Next("Mon: gym")
+	gym2 := ids.Next("Mon: gym")
+	trip := ids.Next("Mar 3 2024: trip")
+
+	tests := map[string]struct {
+		id             string
+		expectedNumber int
+	}{
+		"first duplicate": {
+			id:             gym1,
+			expectedNumber: 2,
+		},
+		"second duplicate": {
+			id:             gym2,
+			expectedNumber: 4,
+		},
+		"after section header": {
+			id:             trip,
+			expectedNumber: 5,
+		},
+		"not found": {
+			id:             "0000000",
+			expectedNumber: 0,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			f, err := ReadFile(strings.NewReader(input), TypeWeekly)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			l := f.Find("tasks.txt", test.id)
+			if test.expectedNumber == 0 {
+				if l != nil {
+					t.Fatalf("expected no line, found line %d", l.Number)
+				}
+				return
+			}
+			if l == nil {
+				t.Fatal("expected line not found")
+			}
+			if l.Number != test.expectedNumber {
+				t.Fatalf("result line number %d not equal to expected line number %d", l.Number, test.expectedNumber)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package sources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/calendar"
+)
+
+// prefix of the dates of floating tasks
+const floatingPrefix = "every"
+
+// Floating represents a task that recurs a number of days after it was last completed rather than on
+// fixed dates
+type Floating struct {
+	meta
+
+	interval int
+	weeks    bool
+	anchor   *Single
+	lastDone time.Time
+	today    time.Time
+	text     string
+}
+
+// NewFloating constructs a Floating from a date of the form "every <n>d" or "every <n>w", optionally
+// followed by "from <date>" to set the date on which the task is first due
+func NewFloating(raw *RawTask) (*Floating, error) {
+	date := strings.ToLower(raw.Date)
+	if !isFloatingDate(date) {
+		return &Floating{}, fmt.Errorf("invalid floating date [%s]", raw.Date)
+	}
+	date = strings.TrimSpace(strings.TrimPrefix(date, floatingPrefix))
+
+	anchorStr := ""
+	if i := strings.Index(date, " from "); i >= 0 {
+		anchorStr = strings.TrimSpace(date[i+len(" from "):])
+		date = strings.TrimSpace(date[:i])
+	}
+
+	interval, weeks, err := parseInterval(date)
+	if err != nil {
+		return &Floating{}, fmt.Errorf("invalid floating date [%s]: %v", raw.Date, err)
+	}
+
+	f := &Floating{
+		interval: interval,
+		weeks:    weeks,
+		text:     raw.Text,
+		meta:     newMeta(raw, TypeFloating),
+	}
+	if anchorStr != "" {
+		anchor, err := NewSingle(&RawTask{Date: anchorStr})
+		if err != nil {
+			return &Floating{}, fmt.Errorf("invalid floating date [%s]: %v", raw.Date, err)
+		}
+		f.anchor = anchor
+	}
+	return f, nil
+}
+
+// isFloatingDate reports whether a date is that of a floating task
+func isFloatingDate(date string) bool {
+	fields := strings.Fields(strings.ToLower(date))
+	return len(fields) > 1 && fields[0] == floatingPrefix
+}
+
+// parseInterval parses an interval of the form "<n>d", "<n>w", "<n> days", or "<n> weeks", returning
+// the number of days and whether the interval was specified in weeks
+func parseInterval(s string) (int, bool, error) {
+	s = strings.ReplaceAll(s, " ", "")
+	i := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	if i <= 0 {
+		return 0, false, fmt.Errorf("interval [%s] must be a number of days or weeks", s)
+	}
+	n, err := strconv.Atoi(s[:i])
+	if err != nil || n <= 0 {
+		return 0, false, fmt.Errorf("interval [%s] must be a positive number of days or weeks", s)
+	}
+
+	switch s[i:] {
+	case "d", "day", "days":
+		return n, false, nil
+	case "w", "week", "weeks":
+		return 7 * n, true, nil
+	}
+	return 0, false, fmt.Errorf("unknown unit [%s] of interval, must be d or w", s[i:])
+}
+
+// SetToday sets today's date, on which a task that has not been completed and has no anchor date is due
+func (f *Floating) SetToday(date time.Time) {
+	f.today = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// SetLastDone sets the date on which the task was last completed, from which its next due date is
+// calculated
+func (f *Floating) SetLastDone(date time.Time) {
+	f.lastDone = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Due returns the date on which the task is next due, which is the interval after the date on which
+// it was last completed or, if it has not been completed, its anchor date. A task without either date
+// is due today if today's date is set and otherwise on the reference date.
+func (f *Floating) Due(t time.Time) time.Time {
+	switch {
+	case !f.lastDone.IsZero():
+		return f.lastDone.AddDate(0, 0, f.interval)
+	case f.anchor != nil:
+		return time.Date(f.anchor.year, f.anchor.month, f.anchor.day, 0, 0, 0, 0, time.UTC)
+	case !f.today.IsZero():
+		return f.today
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DaysFrom calculates the number of days until a task is due, which is negative if it is overdue
+func (f *Floating) DaysFrom(t time.Time) int {
+	t = f.in(t)
+	due := f.Due(t)
+	fTime := time.Date(due.Year(), due.Month(), due.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	days := calendar.UnixToDaysFloored(fTime.Unix() - t.Unix())
+	return int(days)
+}
+
+// Type returns the type of the task
+func (f *Floating) Type() Type {
+	return TypeFloating
+}
+
+// Date returns the task's date in canonical form
+func (f *Floating) Date() string {
+	date := fmt.Sprintf("%s %dd", floatingPrefix, f.interval)
+	if f.weeks {
+		date = fmt.Sprintf("%s %dw", floatingPrefix, f.interval/7)
+	}
+	if f.anchor != nil {
+		date = fmt.Sprintf("%s from %s", date, f.anchor.Date())
+	}
+	return date
+}
+
+// Validate returns an error if the task's anchor date does not exist
+func (f *Floating) Validate() error {
+	if f.anchor == nil {
+		return nil
+	}
+	return f.anchor.Validate()
+}
+
+func (f *Floating) String() string {
+	return f.text
+}
@@ -0,0 +1,210 @@
+package sources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFloatingDaysFrom(t *testing.T) {
+	now := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		date     string
+		today    time.Time
+		lastDone time.Time
+		expected int
+	}{
+		"no history or anchor": {
+			date:     "every 14d",
+			expected: 0,
+		},
+		"no history or anchor is due today": {
+			date:     "every 14d",
+			today:    time.Date(2024, time.March, 9, 12, 0, 0, 0, time.UTC),
+			expected: 3,
+		},
+		"anchor before today": {
+			date:     "every 14d from 2024-03-01",
+			today:    time.Date(2024, time.March, 9, 12, 0, 0, 0, time.UTC),
+			expected: -5,
+		},
+		"future anchor": {
+			date:     "every 14d from 2024-03-10",
+			expected: 4,
+		},
+		"past anchor": {
+			date:     "every 14d from 2024-03-01",
+			expected: -5,
+		},
+		"last done": {
+			date:     "every 14d from 2024-01-01",
+			lastDone: time.Date(2024, time.February, 28, 20, 0, 0, 0, time.UTC),
+			expected: 7,
+		},
+		"last done in weeks": {
+			date:     "every 1w",
+			lastDone: time.Date(2024, time.February, 27, 0, 0, 0, 0, time.UTC),
+			expected: -1,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			f, err := NewFloating(&RawTask{Date: test.date})
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if !test.today.IsZero() {
+				f.SetToday(test.today)
+			}
+			if !test.lastDone.IsZero() {
+				f.SetLastDone(test.lastDone)
+			}
+			result := f.DaysFrom(now)
+			if result != test.expected {
+				t.Fatalf("result days %d not equal to expected days %d", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewFloating(t *testing.T) {
+	tests := map[string]struct {
+		raw      *RawTask
+		expected *Floating
+	}{
+		"days": {
+			raw: &RawTask{
+				Date: "every 14d",
+				Text: "mow",
+			},
+			expected: &Floating{
+				interval: 14,
+				text:     "mow",
+			},
+		},
+		"weeks spelled out": {
+			raw: &RawTask{
+				Date: "Every 3 Weeks",
+				Text: "mow",
+			},
+			expected: &Floating{
+				interval: 21,
+				weeks:    true,
+				text:     "mow",
+			},
+		},
+		"anchor": {
+			raw: &RawTask{
+				Date: "every 1 day from mar 3 2024",
+				Text: "mow",
+			},
+			expected: &Floating{
+				interval: 1,
+				anchor:   &Single{day: 3, month: time.March, year: 2024},
+				text:     "mow",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result, err := NewFloating(test.raw)
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if result.text != test.expected.text {
+				t.Fatalf("result text '%s' not equal to expected text '%s'", result.text, test.expected.text)
+			}
+			if result.interval != test.expected.interval {
+				t.Fatalf("result interval %d not equal to expected interval %d", result.interval, test.expected.interval)
+			}
+			if result.weeks != test.expected.weeks {
+				t.Fatalf("result weeks %t not equal to expected weeks %t", result.weeks, test.expected.weeks)
+			}
+			if (result.anchor == nil) != (test.expected.anchor == nil) {
+				t.Fatalf("result anchor %v not equal to expected anchor %v", result.anchor, test.expected.anchor)
+			}
+			if result.anchor != nil && result.anchor.Date() != test.expected.anchor.Date() {
+				t.Fatalf("result anchor '%s' not equal to expected anchor '%s'", result.anchor.Date(), test.expected.anchor.Date())
+			}
+		})
+	}
+}
+
+func TestFloatingDate(t *testing.T) {
+	tests := map[string]struct {
+		date     string
+		expected string
+	}{
+		"days": {
+			date:     "every 10 days",
+			expected: "every 10d",
+		},
+		"weeks with anchor": {
+			date:     "Every 2w from 2024-03-03",
+			expected: "every 2w from Mar 3 2024",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			r, err := NewFloating(&RawTask{Date: test.date})
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			result := r.Date()
+			if result != test.expected {
+				t.Fatalf("result date '%s' not equal to expected date '%s'", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewFloatingError(t *testing.T) {
+	tests := map[string]struct {
+		raw *RawTask
+	}{
+		"empty": {
+			raw: &RawTask{},
+		},
+		"missing interval": {
+			raw: &RawTask{
+				Date: "every",
+			},
+		},
+		"zero interval": {
+			raw: &RawTask{
+				Date: "every 0d",
+			},
+		},
+		"unknown unit": {
+			raw: &RawTask{
+				Date: "every 2 months",
+			},
+		},
+		"missing number": {
+			raw: &RawTask{
+				Date: "every week",
+			},
+		},
+		"invalid anchor": {
+			raw: &RawTask{
+				Date: "every 2d from tomorrow",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			_, err := NewFloating(test.raw)
+			if err == nil {
+				t.Fatal("unexpected nil error")
+			}
+		})
+	}
+}
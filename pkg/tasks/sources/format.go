@@ -0,0 +1,276 @@
+package sources
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+// Format rewrites the contents of a source file of the specified type in canonical form:
+//   - dates are written in canonical form (e.g., "wednesday" becomes "Wed" and "April 3" becomes
+//     "Apr 3") and the dates of each line are sorted
+//   - the dates are followed by a colon and a single space, with the text quoted only if required
+//   - lines are sorted by date and then text within each section of the file
+//   - continued lines are joined and blank lines are removed, except to separate sections and
+//     standalone comments
+//
+// Comments directly preceding a task move with it when sorting, as do comments separated from a task
+// by a blank line unless they are at the start or end of a section, where they remain in place. The
+// header of the file, through its last directive before the first task or section header, is kept
+// unchanged at the top of the file.
+func Format(r io.Reader, typ Type) ([]byte, error) {
+	type scannedLine struct {
+		text   string
+		number int
+	}
+	lines := []scannedLine{}
+	scanner := NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scannedLine{text: scanner.Text(), number: scanner.Line()})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	header := []string{}
+	for i, l := range lines {
+		line := cleanString(l.text)
+		if line != "" && !IsComment(line) {
+			break
+		}
+		if IsDirective(line) {
+			header = header[:0]
+			for _, h := range lines[:i+1] {
+				header = append(header, strings.TrimRight(h.text, " \t"))
+			}
+		}
+	}
+	lines = lines[len(header):]
+
+	sections := []*formatSection{{typ: typ}}
+	cur := sections[0]
+	pending := []string{}
+
+	// flushComments moves pending comments into a standalone block
+	flushComments := func() {
+		if len(pending) == 0 {
+			return
+		}
+		if len(cur.entries) == 0 {
+			cur.top = append(cur.top, pending)
+		} else {
+			cur.bottom = append(cur.bottom, pending)
+		}
+		pending = []string{}
+	}
+
+	for _, l := range lines {
+		line := cleanString(l.text)
+		switch {
+		case line == "":
+			flushComments()
+		case IsComment(line):
+			pending = append(pending, line)
+		default:
+			if sectionTyp, ok := ParseSectionHeader(line); ok {
+				flushComments()
+				header := "[" + string(sectionTyp) + "]"
+				if comment := trailingComment(line); comment != "" {
+					header += " # " + comment
+				}
+				cur = &formatSection{typ: sectionTyp, header: header}
+				sections = append(sections, cur)
+				continue
+			}
+
+			entry, err := formatLine(line, cur.typ)
+			if err != nil {
+				if perr, ok := err.(*ParseError); ok {
+					perr.Line = l.number
+				}
+				return nil, err
+			}
+			// standalone comments in the middle of a section move with the following task
+			for _, block := range cur.bottom {
+				entry.comments = append(entry.comments, block...)
+			}
+			cur.bottom = nil
+			entry.comments = append(entry.comments, pending...)
+			pending = []string{}
+			cur.entries = append(cur.entries, entry)
+		}
+	}
+	flushComments()
+
+	var b strings.Builder
+	if len(header) > 0 {
+		b.WriteString(strings.Join(header, "\n"))
+		b.WriteString("\n")
+	}
+	for _, section := range sections {
+		for _, chunk := range section.chunks() {
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			for _, line := range chunk {
+				b.WriteString(line)
+				b.WriteString("\n")
+			}
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+type formatSection struct {
+	typ     Type
+	header  string
+	top     [][]string
+	entries []*formatEntry
+	bottom  [][]string
+}
+
+type formatEntry struct {
+	comments []string
+	line     string
+
+	rank int
+	key  int
+	text string
+}
+
+// chunks returns the groups of lines of a section, which are separated by blank lines when written
+func (s *formatSection) chunks() [][]string {
+	chunks := [][]string{}
+	chunks = append(chunks, s.top...)
+
+	sort.SliceStable(s.entries, func(i, j int) bool {
+		ei, ej := s.entries[i], s.entries[j]
+		if ei.rank != ej.rank {
+			return ei.rank < ej.rank
+		}
+		if ei.key != ej.key {
+			return ei.key < ej.key
+		}
+		return strings.ToLower(ei.text) < strings.ToLower(ej.text)
+	})
+	if len(s.entries) > 0 {
+		lines := []string{}
+		for _, entry := range s.entries {
+			lines = append(lines, entry.comments...)
+			lines = append(lines, entry.line)
+		}
+		chunks = append(chunks, lines)
+	}
+
+	chunks = append(chunks, s.bottom...)
+
+	if s.header == "" {
+		return chunks
+	}
+	if len(chunks) == 0 {
+		return [][]string{{s.header}}
+	}
+	chunks[0] = append([]string{s.header}, chunks[0]...)
+	return chunks
+}
+
+// formatLine parses a task line and writes it in canonical form
+func formatLine(line string, typ Type) (*formatEntry, error) {
+	l, err := Tokenize(line)
+	if err != nil {
+		return nil, err
+	}
+
+	type formatDate struct {
+		date string
+		rank int
+		key  int
+	}
+	dates := []formatDate{}
+	for i, date := range l.Dates {
+		r, err := newRule(typ, &RawTask{Date: date, Text: l.Text})
+		if err != nil {
+			return nil, &ParseError{Column: l.DateColumns[i], Msg: err.Error()}
+		}
+		dates = append(dates, formatDate{date: r.Date(), rank: typeRank(r.Type()), key: r.sortKey()})
+	}
+	sort.SliceStable(dates, func(i, j int) bool {
+		if dates[i].rank != dates[j].rank {
+			return dates[i].rank < dates[j].rank
+		}
+		return dates[i].key < dates[j].key
+	})
+
+	dateStrs := []string{}
+	for _, d := range dates {
+		dateStrs = append(dateStrs, d.date)
+	}
+
+	entry := &formatEntry{
+		line: FormatLine(&Line{Dates: dateStrs, Text: l.Text, Comment: l.Comment, Reminders: l.Reminders, Label: l.Label}),
+		rank: dates[0].rank,
+		key:  dates[0].key,
+		text: l.Text,
+	}
+	return entry, nil
+}
+
+// FormatLine writes a tokenized line, quoting its text if required for it to be parsed unchanged
+func FormatLine(l *Line) string {
+	var b strings.Builder
+	for i, date := range l.Dates {
+		if i > 0 {
+			b.WriteRune(multiDateSeparator)
+		}
+		b.WriteString(escapeDate(date))
+	}
+	if len(l.Reminders) > 0 {
+		b.WriteString(" ")
+		b.WriteString(formatReminders(l.Reminders))
+	}
+	if l.Label != "" {
+		b.WriteString(" ")
+		b.WriteString(formatLabel(l.Label))
+	}
+	b.WriteRune(dateTextSeparator)
+
+	if l.Text != "" {
+		b.WriteString(" ")
+		b.WriteString(formatText(l.Text))
+	}
+	if l.Comment != "" {
+		b.WriteString(" ")
+		b.WriteRune(commentMarker)
+		b.WriteString(" ")
+		b.WriteString(l.Comment)
+	}
+	return b.String()
+}
+
+// formatText returns text as is if it is parsed unchanged and otherwise quotes it
+func formatText(text string) string {
+	if l, err := Tokenize("x: " + text); err == nil && l.Text == text && l.Comment == "" {
+		return text
+	}
+	var b strings.Builder
+	b.WriteRune(quote)
+	for _, r := range text {
+		if r == quote || r == escape {
+			b.WriteRune(escape)
+		}
+		b.WriteRune(r)
+	}
+	b.WriteRune(quote)
+	return b.String()
+}
+
+func escapeDate(date string) string {
+	var b strings.Builder
+	for i, r := range date {
+		if r == escape || r == multiDateSeparator || r == dateTextSeparator || (i == 0 && r == commentMarker) {
+			b.WriteRune(escape)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package sources
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := map[string]struct {
+		typ      Type
+		input    string
+		expected string
+	}{
+		"empty": {
+			typ:      TypeWeekly,
+			input:    "",
+			expected: "",
+		},
+		"normalizes and sorts weekly": {
+			typ:      TypeWeekly,
+			input:    "wednesday:   garbage\n\nsunday :Play\nsat/MON: gym\n",
+			expected: "Sun: Play\nMon/Sat: gym\nWed: garbage\n",
+		},
+		"sorts by text within date": {
+			typ:      TypeMonthly,
+			input:    "15: b\n1: z\n15: A\n",
+			expected: "1: z\n15: A\n15: b\n",
+		},
+		"normalizes annual": {
+			typ:      TypeAnnual,
+			input:    "April 15: taxes\njanuary 2: party\n",
+			expected: "Jan 2: party\nApr 15: taxes\n",
+		},
+		"normalizes single": {
+			typ:      TypeSingle,
+			input:    "2024-03-03: trip\nMarch 2 2024: pack\n",
+			expected: "Mar 2 2024: pack\nMar 3 2024: trip\n",
+		},
+		"header directives stay at the top": {
+			typ:      TypeWeekly,
+			input:    "#+label: work\n#+color: red\nWed: zeta\nMon: alpha",
+			expected: "#+label: work\n#+color: red\n\nMon: alpha\nWed: zeta\n",
+		},
+		"header with comments stays unchanged": {
+			typ:      TypeWeekly,
+			input:    "# work tasks\n#+label:   work\n\n#+tags: office\n# standups\nwednesday: zeta\nMon: alpha\n",
+			expected: "# work tasks\n#+label:   work\n\n#+tags: office\n\nMon: alpha\n# standups\nWed: zeta\n",
+		},
+		"mixed types sorted by type": {
+			typ:      TypeAuto,
+			input:    "Mar 3 2024: d\nMar 3: c\n15: b\nMon: a\n",
+			expected: "Mon: a\n15: b\nMar 3: c\nMar 3 2024: d\n",
+		},
+		"deadlines sorted after other types by due date": {
+			typ:      TypeAuto,
+			input:    "due 2024-04-15 from 2024-03-15: e\nDUE march 1 2024: f\nMar 3 2024: d\nevery 2w: g\n",
+			expected: "Mar 3 2024: d\nevery 2w: g\ndue Mar 1 2024: f\ndue Apr 15 2024 from Mar 15 2024: e\n",
+		},
+		"comments move with tasks": {
+			typ:      TypeWeekly,
+			input:    "# header\n\n# about wed\nwed: b  # trailing\n# about mon\nmon: a\n\n# footer\n",
+			expected: "# header\n\n# about mon\nMon: a\n# about wed\nWed: b # trailing\n\n# footer\n",
+		},
+		"standalone comment in middle moves with next task": {
+			typ:      TypeWeekly,
+			input:    "wed: b\n\n# note\n\nmon: a\n",
+			expected: "# note\nMon: a\nWed: b\n",
+		},
+		"sections": {
+			typ:      TypeAuto,
+			input:    "Mon: a\n[ Single ] # trips\n2024-03-03: b\n2024-03-01: c\n[weekly]\n",
+			expected: "Mon: a\n\n[single] # trips\nMar 1 2024: c\nMar 3 2024: b\n\n[weekly]\n",
+		},
+		"quotes text when required": {
+			typ:      TypeWeekly,
+			input:    "mon: \"review # 42\"\ntue: \"plain\"\nwed: say \"hi\"\nthu: \"say \\\"hi\\\"\"\n",
+			expected: "Mon: \"review # 42\"\nTue: plain\nWed: say \"hi\"\nThu: say \"hi\"\n",
+		},
+		"normalizes reminders": {
+			typ:      TypeAnnual,
+			input:    "january 12 [REMIND 1d, 1w]: birthday\n",
+			expected: "Jan 12 [remind 7d,1d]: birthday\n",
+		},
+		"normalizes labels and relative tasks": {
+			typ:      TypeAuto,
+			input:    "2 weeks AFTER Party: thank you notes\n3d before party: shop\nMar 3 [LABEL Party] [remind 1d]: party\n",
+			expected: "Mar 3 [remind 1d] [label party]: party\n3d before party: shop\n2w after party: thank you notes\n",
+		},
+		"joins continued lines": {
+			typ:      TypeWeekly,
+			input:    "mon: clean \\\n   gutters\n",
+			expected: "Mon: clean gutters\n",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result, err := Format(strings.NewReader(test.input), test.typ)
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if string(result) != test.expected {
+				t.Fatalf("result:\n%s\nnot equal to expected:\n%s", result, test.expected)
+			}
+
+			// formatting is idempotent
+			again, err := Format(strings.NewReader(string(result)), test.typ)
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if string(again) != string(result) {
+				t.Fatalf("reformatted result:\n%s\nnot equal to result:\n%s", again, result)
+			}
+		})
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	_, err := Format(strings.NewReader("mon: a\n\nfunday: b\n"), TypeWeekly)
+	perr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("expected ParseError, got: %v", err)
+	}
+	if perr.Line != 3 || perr.Column != 1 {
+		t.Fatalf("result error position %d:%d not equal to expected position 3:1", perr.Line, perr.Column)
+	}
+}
@@ -0,0 +1,85 @@
+package sources
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// prefix of the header directive lines of a source file
+const directivePrefix = "#+"
+
+// header directive keys
+const (
+	directiveLabel    = "label"
+	directiveTags     = "tags"
+	directiveColor    = "color"
+	directiveTimezone = "timezone"
+)
+
+// Colors are the names of the colors that can be set for the tasks of a source file
+var Colors = []string{"red", "green", "yellow", "blue", "purple", "cyan", "gray"}
+
+// Header holds the settings of a source file that apply to every task in it, set by directive lines of the
+// form "#+<key>: <value>" before the first task of the file
+type Header struct {
+	// Label names the source file in the output
+	Label string
+	// Tags are added to the tags of every task
+	Tags []string
+	// Color is the color in which tasks are displayed, one of Colors
+	Color string
+	// Location is the timezone in which the dates of tasks are evaluated
+	Location *time.Location
+}
+
+// IsDirective reports whether a line is a header directive, which is otherwise a comment
+func IsDirective(line string) bool {
+	return strings.HasPrefix(cleanString(line), directivePrefix)
+}
+
+// Apply sets the Header's setting from a directive line
+func (h *Header) Apply(line string) error {
+	directive := strings.TrimPrefix(cleanString(line), directivePrefix)
+	parts := strings.SplitN(directive, ":", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid header directive [%s], must be of the form %s<key>: <value>", line, directivePrefix)
+	}
+	key := strings.ToLower(cleanString(parts[0]))
+	value := cleanString(parts[1])
+
+	switch key {
+	case directiveLabel:
+		h.Label = value
+	case directiveTags:
+		h.Tags = []string{}
+		for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+			h.Tags = append(h.Tags, strings.ToLower(strings.TrimPrefix(tag, string(tagPrefix))))
+		}
+	case directiveColor:
+		color := strings.ToLower(value)
+		if !isColor(color) {
+			return fmt.Errorf("invalid header color [%s], must be one of [%s]", value, strings.Join(Colors, ", "))
+		}
+		h.Color = color
+	case directiveTimezone:
+		loc, err := time.LoadLocation(value)
+		if err != nil {
+			return fmt.Errorf("invalid header timezone [%s]: %v", value, err)
+		}
+		h.Location = loc
+	default:
+		return fmt.Errorf("unknown header directive [%s], must be one of [%s, %s, %s, %s]",
+			key, directiveLabel, directiveTags, directiveColor, directiveTimezone)
+	}
+	return nil
+}
+
+func isColor(color string) bool {
+	for _, c := range Colors {
+		if c == color {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package sources
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeaderApply(t *testing.T) {
+	tests := map[string]struct {
+		lines    []string
+		expected *Header
+	}{
+		"label": {
+			lines:    []string{"#+label: work"},
+			expected: &Header{Label: "work"},
+		},
+		"tags with spaces, commas, and prefixes": {
+			lines:    []string{"#+tags: Work, #office home"},
+			expected: &Header{Tags: []string{"work", "office", "home"}},
+		},
+		"color with capitals": {
+			lines:    []string{"  #+Color: Blue"},
+			expected: &Header{Color: "blue"},
+		},
+		"multiple directives": {
+			lines:    []string{"#+label: kids", "#+tags: kids", "#+label: family"},
+			expected: &Header{Label: "family", Tags: []string{"kids"}},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			h := &Header{}
+			for _, line := range test.lines {
+				if !IsDirective(line) {
+					t.Fatalf("expected line [%s] to be a directive", line)
+				}
+				err := h.Apply(line)
+				if err != nil {
+					t.Fatalf("unexpected non-nil error: %v", err)
+				}
+			}
+			if h.Label != test.expected.Label {
+				t.Fatalf("result label '%s' not equal to expected label '%s'", h.Label, test.expected.Label)
+			}
+			if strings.Join(h.Tags, ",") != strings.Join(test.expected.Tags, ",") {
+				t.Fatalf("result tags %v not equal to expected tags %v", h.Tags, test.expected.Tags)
+			}
+			if h.Color != test.expected.Color {
+				t.Fatalf("result color '%s' not equal to expected color '%s'", h.Color, test.expected.Color)
+			}
+		})
+	}
+}
+
+func TestHeaderApplyError(t *testing.T) {
+	tests := map[string]struct {
+		line string
+	}{
+		"missing separator": {
+			line: "#+label work",
+		},
+		"unknown key": {
+			line: "#+owner: alice",
+		},
+		"unknown color": {
+			line: "#+color: chartreuse",
+		},
+		"unknown timezone": {
+			line: "#+timezone: Mars/Olympus_Mons",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			err := (&Header{}).Apply(test.line)
+			if err == nil {
+				t.Fatal("unexpected nil error")
+			}
+		})
+	}
+}
+
+func TestHeaderTimezone(t *testing.T) {
+	location := func(name string) *time.Location {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return loc
+	}
+
+	tests := map[string]struct {
+		timezone string
+		date     string
+		now      time.Time
+		expected int
+	}{
+		"no timezone": {
+			timezone: "",
+			date:     "Thu",
+			now:      time.Date(2024, time.March, 6, 12, 0, 0, 0, location("America/Los_Angeles")),
+			expected: 1,
+		},
+		"same timezone": {
+			timezone: "Asia/Tokyo",
+			date:     "Thu",
+			now:      time.Date(2024, time.March, 7, 12, 0, 0, 0, location("Asia/Tokyo")),
+			expected: 0,
+		},
+		"file ahead of UTC begins on previous day": {
+			timezone: "Asia/Tokyo",
+			date:     "Thu",
+			now:      time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC),
+			expected: 0,
+		},
+		"file ahead of UTC evening": {
+			timezone: "Asia/Tokyo",
+			date:     "Thu",
+			now:      time.Date(2024, time.March, 6, 20, 0, 0, 0, time.UTC),
+			expected: 0,
+		},
+		"file ahead of Los Angeles begins on previous day": {
+			timezone: "Asia/Tokyo",
+			date:     "Thu",
+			now:      time.Date(2024, time.March, 6, 12, 0, 0, 0, location("America/Los_Angeles")),
+			expected: 0,
+		},
+		"file ahead of UTC has passed on same day": {
+			timezone: "Asia/Tokyo",
+			date:     "Thu",
+			now:      time.Date(2024, time.March, 7, 12, 0, 0, 0, time.UTC),
+			expected: 6,
+		},
+		"file ahead by half hours begins on previous day": {
+			timezone: "Asia/Kolkata",
+			date:     "Thu",
+			now:      time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC),
+			expected: 0,
+		},
+		"file behind UTC begins on same day": {
+			timezone: "America/New_York",
+			date:     "Thu",
+			now:      time.Date(2024, time.March, 7, 12, 0, 0, 0, time.UTC),
+			expected: 0,
+		},
+		"file behind Tokyo begins on same day": {
+			timezone: "America/New_York",
+			date:     "Thu",
+			now:      time.Date(2024, time.March, 6, 12, 0, 0, 0, location("Asia/Tokyo")),
+			expected: 1,
+		},
+		"single date": {
+			timezone: "Asia/Tokyo",
+			date:     "Mar 7 2024",
+			now:      time.Date(2024, time.March, 6, 12, 0, 0, 0, location("America/Los_Angeles")),
+			expected: 0,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			h := &Header{}
+			if test.timezone != "" {
+				err := h.Apply("#+timezone: " + test.timezone)
+				if err != nil {
+					t.Fatalf("unexpected non-nil error: %v", err)
+				}
+			}
+			r, err := newRule(TypeAuto, &RawTask{Date: test.date, Text: "standup", Header: h})
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if days := r.DaysFrom(test.now); days != test.expected {
+				t.Fatalf("result days %d not equal to expected days %d", days, test.expected)
+			}
+		})
+	}
+}
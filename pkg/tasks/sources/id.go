@@ -0,0 +1,62 @@
+package sources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// number of hex characters in a task identifier
+const idLength = 7
+
+// LineIDs generates the identifiers of the task lines of a source file, which are stable as long as
+// the file's path and the line's dates and text are unchanged. Each identifier is a short hash of the
+// absolute path of the file, the line's dates in canonical form and sorted, and its text, along with
+// the number of lines with the same dates and text preceding it in the file so that duplicated lines
+// have distinct identifiers. Formatting a line or changing its markers or comment does not change it.
+type LineIDs struct {
+	path string
+	seen map[string]int
+}
+
+// NewLineIDs constructs a LineIDs for a source file
+func NewLineIDs(path string) *LineIDs {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return &LineIDs{
+		path: path,
+		seen: make(map[string]int),
+	}
+}
+
+// Next returns the identifier of the next task line of the file
+func (ids *LineIDs) Next(line string) string {
+	normalized := canonicalLine(line)
+	n := ids.seen[normalized]
+	ids.seen[normalized]++
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s\n%d", ids.path, normalized, n)))
+	return hex.EncodeToString(sum[:])[:idLength]
+}
+
+// canonicalLine returns the sorted canonical dates and the text of a line, with any date of which the
+// type cannot be detected kept as written
+func canonicalLine(line string) string {
+	l, err := Tokenize(line)
+	if err != nil {
+		return cleanString(line)
+	}
+	dates := []string{}
+	for _, date := range l.Dates {
+		if r, err := newRule(TypeAuto, &RawTask{Date: date, Text: l.Text}); err == nil {
+			date = r.Date()
+		}
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	return fmt.Sprintf("%s%c %s", strings.Join(dates, string(multiDateSeparator)), dateTextSeparator, l.Text)
+}
@@ -0,0 +1,114 @@
+package sources
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestLineIDs(t *testing.T) {
+	tests := map[string]struct {
+		path1      string
+		line1      string
+		path2      string
+		line2      string
+		expectSame bool
+	}{
+		"same line": {
+			path1:      "tasks.txt",
+			line1:      "Mon: gym",
+			path2:      "tasks.txt",
+			line2:      "Mon: gym",
+			expectSame: true,
+		},
+		"whitespace and quoting do not change id": {
+			path1:      "tasks.txt",
+			line1:      "Mon: gym  # note",
+			path2:      "./tasks.txt",
+			line2:      "  Mon :\"gym\" # note",
+			expectSame: true,
+		},
+		"different text": {
+			path1:      "tasks.txt",
+			line1:      "Mon: gym",
+			path2:      "tasks.txt",
+			line2:      "Mon: yoga",
+			expectSame: false,
+		},
+		"different path": {
+			path1:      "tasks.txt",
+			line1:      "Mon: gym",
+			path2:      "other.txt",
+			line2:      "Mon: gym",
+			expectSame: false,
+		},
+		"comment and markers do not change id": {
+			path1:      "tasks.txt",
+			line1:      "Mon: gym",
+			path2:      "tasks.txt",
+			line2:      "Mon [remind 1d] [label gym]: gym # note",
+			expectSame: true,
+		},
+		"date spelling and order do not change id": {
+			path1:      "tasks.txt",
+			line1:      "wednesday/monday: gym",
+			path2:      "tasks.txt",
+			line2:      "Mon/Wed: gym",
+			expectSame: true,
+		},
+		"different date": {
+			path1:      "tasks.txt",
+			line1:      "Mon: gym",
+			path2:      "tasks.txt",
+			line2:      "Tue: gym",
+			expectSame: false,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			id1 := NewLineIDs(test.path1).Next(test.line1)
+			id2 := NewLineIDs(test.path2).Next(test.line2)
+			if len(id1) != idLength {
+				t.Fatalf("result id length %d not equal to expected length %d", len(id1), idLength)
+			}
+			if (id1 == id2) != test.expectSame {
+				t.Fatalf("result ids %s and %s: expected same %t", id1, id2, test.expectSame)
+			}
+		})
+	}
+}
+
+func TestLineIDsDuplicates(t *testing.T) {
+	ids := NewLineIDs("tasks.txt")
+	id1 := ids.Next("Mon: gym")
+	id2 := ids.Next("Mon: gym")
+	if id1 == id2 {
+		t.Fatalf("result ids of duplicated lines are equal: %s", id1)
+	}
+}
+
+func TestLineIDsFormat(t *testing.T) {
+	input := "wednesday/monday [REMIND 1w]: gym\n2024-03-03: trip\napril 15:taxes # file early\nwednesday/monday [REMIND 1w]: gym\n"
+
+	lineIDs := func(contents string) []string {
+		ids := NewLineIDs("tasks.txt")
+		result := []string{}
+		for _, line := range strings.Split(strings.TrimSpace(contents), "\n") {
+			result = append(result, ids.Next(line))
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	formatted, err := Format(strings.NewReader(input), TypeAuto)
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	before := lineIDs(input)
+	after := lineIDs(string(formatted))
+	if strings.Join(before, ",") != strings.Join(after, ",") {
+		t.Fatalf("result ids %v after formatting not equal to expected ids %v", after, before)
+	}
+}
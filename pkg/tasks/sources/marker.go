@@ -0,0 +1,59 @@
+package sources
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// brackets of the markers that follow a date
+const (
+	markerStart = '['
+	markerEnd   = ']'
+)
+
+// marker is a bracketed marker following a date, such as "[remind 7d]", made of a keyword and a value
+type marker struct {
+	keyword string
+	value   string
+	// index is the index of the rune that starts the marker in its date
+	index int
+}
+
+// splitMarkers removes the markers from the end of a date, returning the date and the markers. The
+// Column of a returned ParseError is the (0-indexed) index of the rune in the date at which it occurred.
+func splitMarkers(date string) (string, []*marker, error) {
+	runes := []rune(date)
+	start := strings.IndexRune(date, markerStart)
+	if start < 0 {
+		return date, nil, nil
+	}
+	start = len([]rune(date[:start]))
+
+	markers := []*marker{}
+	for i := start; i < len(runes); i++ {
+		if unicode.IsSpace(runes[i]) {
+			continue
+		}
+		if runes[i] != markerStart {
+			return date, nil, &ParseError{Column: i, Msg: "unexpected characters after marker"}
+		}
+		end := i + 1
+		for end < len(runes) && runes[end] != markerEnd {
+			end++
+		}
+		if end == len(runes) {
+			return date, nil, &ParseError{Column: i, Msg: fmt.Sprintf("marker [%s] must end with %c", string(runes[i:]), markerEnd)}
+		}
+
+		fields := strings.Fields(string(runes[i+1 : end]))
+		m := &marker{index: i}
+		if len(fields) > 0 {
+			m.keyword = strings.ToLower(fields[0])
+			m.value = strings.Join(fields[1:], " ")
+		}
+		markers = append(markers, m)
+		i = end
+	}
+	return cleanString(string(runes[:start])), markers, nil
+}
@@ -0,0 +1,211 @@
+package sources
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// prefixes of the tokens of a task's text that set its tags and contexts
+const (
+	tagPrefix     = '#'
+	contextPrefix = '@'
+)
+
+// PriorityNone is the priority of a task without a priority marker, which ranks below every other priority
+const PriorityNone = 0
+
+// Source records where a task was loaded from
+type Source struct {
+	// Type is the type of the task
+	Type Type
+	// Path, Line, and Raw are the path of the source file, the (1-indexed) number of the line, and the
+	// line itself, which are not set for a task that was not loaded from a file
+	Path string
+	Line int
+	Raw  string
+}
+
+func (s Source) String() string {
+	if s.Path == "" {
+		return string(s.Type)
+	}
+	return fmt.Sprintf("%s:%d", s.Path, s.Line)
+}
+
+// meta holds the properties shared by every type of task
+type meta struct {
+	id        string
+	source    Source
+	tags      []string
+	contexts  []string
+	priority  int
+	reminders []int
+	taskLabel string
+
+	label    string
+	color    string
+	location *time.Location
+}
+
+func newMeta(raw *RawTask, typ Type) meta {
+	m := meta{
+		id: raw.ID,
+		source: Source{
+			Type: typ,
+			Path: raw.Path,
+			Line: raw.Line,
+			Raw:  raw.Raw,
+		},
+		tags:      parseTokens(raw.Text, tagPrefix),
+		contexts:  parseTokens(raw.Text, contextPrefix),
+		priority:  parsePriorityMarker(raw.Text),
+		reminders: raw.Reminders,
+		taskLabel: raw.Label,
+	}
+	if h := raw.Header; h != nil {
+		m.tags = mergeNames(h.Tags, m.tags)
+		m.label = h.Label
+		m.color = h.Color
+		m.location = h.Location
+	}
+	return m
+}
+
+// ID returns the identifier of the source file line from which the task was loaded
+func (m *meta) ID() string {
+	return m.id
+}
+
+// Tags returns the lowercase tags set by #tag tokens in the task's text, without the leading #
+func (m *meta) Tags() []string {
+	return m.tags
+}
+
+// Contexts returns the lowercase contexts set by @context tokens in the task's text, without the leading @
+func (m *meta) Contexts() []string {
+	return m.contexts
+}
+
+// Source returns where the task was loaded from
+func (m *meta) Source() Source {
+	return m.source
+}
+
+// Reminders returns the numbers of days before each occurrence of the task on which it is reminded, set by
+// a reminder marker following its date
+func (m *meta) Reminders() []int {
+	return m.reminders
+}
+
+// TaskLabel returns the label set by a label marker following the task's date, by which relative tasks
+// refer to it, if any
+func (m *meta) TaskLabel() string {
+	return m.taskLabel
+}
+
+// Label returns the label of the source file from which the task was loaded, if any
+func (m *meta) Label() string {
+	return m.label
+}
+
+// Color returns the color of the source file from which the task was loaded, if any
+func (m *meta) Color() string {
+	return m.color
+}
+
+// in returns the time at which to evaluate the task's date for the date of a time in its own location,
+// if the source file from which the task was loaded has a timezone. A date in the file's timezone is
+// displayed on the date in the time's location on which it begins, so the result is noon on the date in
+// the file's timezone that begins during the date of the time.
+func (m *meta) in(t time.Time) time.Time {
+	if m.location == nil {
+		return t
+	}
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).In(m.location)
+	date := time.Date(start.Year(), start.Month(), start.Day(), 12, 0, 0, 0, m.location)
+	if start.Hour() != 0 || start.Minute() != 0 || start.Second() != 0 {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// Priority returns the priority of the task set by a marker in its text, from 1 for the highest priority
+// (A) to 26 for (Z), or PriorityNone
+func (m *meta) Priority() int {
+	return m.priority
+}
+
+// parsePriorityMarker returns the priority set by a priority marker that is the first word of a text, which
+// is a letter in parentheses, such as (A), or !! for priority (A) and ! for priority (B)
+func parsePriorityMarker(text string) int {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return PriorityNone
+	}
+	word := words[0]
+	if strings.Trim(word, "!") == "" {
+		if len(word) > 1 {
+			return 1
+		}
+		return 2
+	}
+	if !strings.HasPrefix(word, "(") || !strings.HasSuffix(word, ")") {
+		return PriorityNone
+	}
+	if p, err := ParsePriority(word); err == nil {
+		return p
+	}
+	return PriorityNone
+}
+
+// ParsePriority parses a priority written as a letter, optionally in parentheses, or as !! or !
+func ParsePriority(s string) (int, error) {
+	switch s {
+	case "!!":
+		return 1, nil
+	case "!":
+		return 2, nil
+	}
+	letter := strings.TrimSuffix(strings.TrimPrefix(s, "("), ")")
+	if len(letter) != 1 || letter[0] < 'A' || letter[0] > 'Z' {
+		return PriorityNone, fmt.Errorf("invalid priority [%s], must be a letter from A to Z, optionally in parentheses, or !! or !", s)
+	}
+	return int(letter[0]-'A') + 1, nil
+}
+
+// mergeNames returns the unique names of two lists, in order
+func mergeNames(a []string, b []string) []string {
+	merged := []string{}
+	seen := make(map[string]bool)
+	for _, name := range append(append([]string{}, a...), b...) {
+		if !seen[name] {
+			seen[name] = true
+			merged = append(merged, name)
+		}
+	}
+	return merged
+}
+
+// parseTokens returns the unique lowercase names of the words of a text that start with a prefix followed
+// by a letter, ignoring any surrounding brackets, quotes, and punctuation, so that a number such as #5 is
+// not a tag
+func parseTokens(text string, prefix rune) []string {
+	names := []string{}
+	seen := make(map[string]bool)
+	for _, word := range strings.Fields(text) {
+		word = strings.TrimLeft(word, "([{\"'")
+		if word == "" || []rune(word)[0] != prefix {
+			continue
+		}
+		name := strings.TrimRightFunc(word[1:], unicode.IsPunct)
+		name = strings.ToLower(name)
+		if name == "" || !unicode.IsLetter([]rune(name)[0]) || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,119 @@
+package sources
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMetaTokens(t *testing.T) {
+	tests := map[string]struct {
+		text             string
+		expectedTags     []string
+		expectedContexts []string
+	}{
+		"none": {
+			text:             "walk dog",
+			expectedTags:     []string{},
+			expectedContexts: []string{},
+		},
+		"tags and contexts": {
+			text:             "deploy freeze #Work @office #release",
+			expectedTags:     []string{"work", "release"},
+			expectedContexts: []string{"office"},
+		},
+		"trailing punctuation": {
+			text:             "pick up kids (@school), #kids!",
+			expectedTags:     []string{"kids"},
+			expectedContexts: []string{"school"},
+		},
+		"duplicates": {
+			text:             "#home clean #HOME",
+			expectedTags:     []string{"home"},
+			expectedContexts: []string{},
+		},
+		"prefix inside word": {
+			text:             "email bob@example.com about issue#12 and #5 # @",
+			expectedTags:     []string{},
+			expectedContexts: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			m := newMeta(&RawTask{Text: test.text}, TypeWeekly)
+			if strings.Join(m.Tags(), ",") != strings.Join(test.expectedTags, ",") {
+				t.Fatalf("result tags %v not equal to expected tags %v", m.Tags(), test.expectedTags)
+			}
+			if strings.Join(m.Contexts(), ",") != strings.Join(test.expectedContexts, ",") {
+				t.Fatalf("result contexts %v not equal to expected contexts %v", m.Contexts(), test.expectedContexts)
+			}
+		})
+	}
+}
+
+func TestMetaPriority(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		expected int
+	}{
+		"none": {
+			text:     "walk dog",
+			expected: PriorityNone,
+		},
+		"letter": {
+			text:     "(A) file taxes",
+			expected: 1,
+		},
+		"later letter": {
+			text:     "(C) file taxes",
+			expected: 3,
+		},
+		"double exclamation": {
+			text:     "!! file taxes",
+			expected: 1,
+		},
+		"triple exclamation": {
+			text:     "!!! file taxes",
+			expected: 1,
+		},
+		"single exclamation": {
+			text:     "! file taxes",
+			expected: 2,
+		},
+		"first marker": {
+			text:     "(B) file taxes !!",
+			expected: 2,
+		},
+		"not markers": {
+			text:     "wow! (a) (AB) (A call",
+			expected: PriorityNone,
+		},
+		"letter after first word": {
+			text:     "Read chapter (I)",
+			expected: PriorityNone,
+		},
+		"exclamation after first word": {
+			text:     "Wow !",
+			expected: PriorityNone,
+		},
+		"double exclamation after first word": {
+			text:     "file taxes !!",
+			expected: PriorityNone,
+		},
+		"marker after leading spaces": {
+			text:     "  (D) file taxes",
+			expected: 4,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			m := newMeta(&RawTask{Text: test.text}, TypeWeekly)
+			if m.Priority() != test.expected {
+				t.Fatalf("result priority %d not equal to expected priority %d", m.Priority(), test.expected)
+			}
+		})
+	}
+}
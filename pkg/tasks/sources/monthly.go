@@ -10,6 +10,8 @@ import (
 
 // Monthly represents a monthly task
 type Monthly struct {
+	meta
+
 	day  int
 	text string
 }
@@ -27,12 +29,14 @@ func NewMonthly(raw *RawTask) (*Monthly, error) {
 	m := &Monthly{
 		day:  int(day),
 		text: raw.Text,
+		meta: newMeta(raw, TypeMonthly),
 	}
 	return m, nil
 }
 
 // DaysFrom calculates the number of days until a task's date
 func (m *Monthly) DaysFrom(t time.Time) int {
+	t = m.in(t)
 	// handle the case where the day is bigger than the number of days in the month
 	if d := calendar.DaysInMonth(t.AddDate(0, -1, 0)); d < m.day {
 		diff := m.day - (t.Day() + d)
@@ -48,6 +52,16 @@ func (m *Monthly) DaysFrom(t time.Time) int {
 	return diff + calendar.DaysInMonth(t)
 }
 
+// Type returns the type of the task
+func (m *Monthly) Type() Type {
+	return TypeMonthly
+}
+
+// Date returns the task's date in canonical form
+func (m *Monthly) Date() string {
+	return strconv.Itoa(m.day)
+}
+
 func (m *Monthly) String() string {
 	return m.text
 }
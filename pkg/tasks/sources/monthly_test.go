@@ -122,6 +122,36 @@ func TestNewMonthly(t *testing.T) {
 	}
 }
 
+func TestMonthlyDate(t *testing.T) {
+	tests := map[string]struct {
+		date     string
+		expected string
+	}{
+		"leading zero": {
+			date:     "05",
+			expected: "5",
+		},
+		"no leading zero": {
+			date:     "31",
+			expected: "31",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			r, err := NewMonthly(&RawTask{Date: test.date})
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			result := r.Date()
+			if result != test.expected {
+				t.Fatalf("result date '%s' not equal to expected date '%s'", result, test.expected)
+			}
+		})
+	}
+}
+
 func TestNewMonthlyError(t *testing.T) {
 	tests := map[string]struct {
 		raw *RawTask
@@ -0,0 +1,61 @@
+package sources
+
+import "time"
+
+// Prune removes the dates of single tasks that occur before a date from a File, removing any line
+// for which no dates remain, and returns the removed tasks as lines. Lines that cannot be parsed are
+// left unchanged.
+func Prune(f *File, before time.Time) []string {
+	pruned := []string{}
+
+	for _, fl := range f.Lines {
+		if !fl.IsTask() {
+			continue
+		}
+		l, err := Tokenize(fl.Text)
+		if err != nil {
+			continue
+		}
+
+		kept := []string{}
+		expired := []string{}
+		for _, date := range l.Dates {
+			if isExpiredSingle(date, fl.Type, before) {
+				expired = append(expired, date)
+			} else {
+				kept = append(kept, date)
+			}
+		}
+		if len(expired) == 0 {
+			continue
+		}
+
+		pruned = append(pruned, FormatLine(&Line{Dates: expired, Text: l.Text, Comment: l.Comment, Reminders: l.Reminders, Label: l.Label}))
+		if len(kept) == 0 {
+			fl.Remove()
+			continue
+		}
+		fl.Replace(FormatLine(&Line{Dates: kept, Text: l.Text, Comment: l.Comment, Reminders: l.Reminders, Label: l.Label}))
+	}
+	return pruned
+}
+
+// isExpiredSingle reports whether a date of a line in a section of the specified type is a valid
+// single task date before another date
+func isExpiredSingle(date string, typ Type, before time.Time) bool {
+	if typ == TypeAuto {
+		detected, err := DetectType(date)
+		if err != nil {
+			return false
+		}
+		typ = detected
+	}
+	if typ != TypeSingle {
+		return false
+	}
+	s, err := NewSingle(&RawTask{Date: date})
+	if err != nil {
+		return false
+	}
+	return s.DaysFrom(before) < 0
+}
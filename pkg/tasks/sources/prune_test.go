@@ -0,0 +1,78 @@
+package sources
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrune(t *testing.T) {
+	before := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.Local)
+
+	tests := map[string]struct {
+		typ            Type
+		input          string
+		expected       string
+		expectedPruned []string
+	}{
+		"nothing to prune": {
+			typ:            TypeSingle,
+			input:          "Jan 1 2024: today\nFeb 3 2025: later\n",
+			expected:       "Jan 1 2024: today\nFeb 3 2025: later\n",
+			expectedPruned: []string{},
+		},
+		"removes past single tasks": {
+			typ:            TypeSingle,
+			input:          "# trips\nDec 31 2023: past\n\n2024-03-01: future\n2023-06-01: \"also past\" # note\n",
+			expected:       "# trips\n\n2024-03-01: future\n",
+			expectedPruned: []string{"Dec 31 2023: past", "2023-06-01: also past # note"},
+		},
+		"keeps future dates of multi-date lines": {
+			typ:            TypeSingle,
+			input:          "Jan 3 2023/Jan 3 2025: dentist\n",
+			expected:       "Jan 3 2025: dentist\n",
+			expectedPruned: []string{"Jan 3 2023: dentist"},
+		},
+		"removes continued lines": {
+			typ:            TypeSingle,
+			input:          "Jan 3 2023: long \\\n  task\nJan 3 2025: b\n",
+			expected:       "Jan 3 2025: b\n",
+			expectedPruned: []string{"Jan 3 2023: long task"},
+		},
+		"detects single tasks in mixed files": {
+			typ:            TypeAuto,
+			input:          "Mon: weekly\nJan 3: annual\n3: monthly\nJan 3 2023: single\n",
+			expected:       "Mon: weekly\nJan 3: annual\n3: monthly\n",
+			expectedPruned: []string{"Jan 3 2023: single"},
+		},
+		"single sections": {
+			typ:            TypeWeekly,
+			input:          "Mon: weekly\n[single]\nJan 3 2023: single\n",
+			expected:       "Mon: weekly\n[single]\n",
+			expectedPruned: []string{"Jan 3 2023: single"},
+		},
+		"ignores invalid lines": {
+			typ:            TypeSingle,
+			input:          "no separator\nJan 3 2023 x: invalid date\n",
+			expected:       "no separator\nJan 3 2023 x: invalid date\n",
+			expectedPruned: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			f, err := ReadFile(strings.NewReader(test.input), test.typ)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			pruned := Prune(f, before)
+			if string(f.Bytes()) != test.expected {
+				t.Fatalf("result %q not equal to expected %q", string(f.Bytes()), test.expected)
+			}
+			if strings.Join(pruned, "|") != strings.Join(test.expectedPruned, "|") {
+				t.Fatalf("result %v not equal to expected %v", pruned, test.expectedPruned)
+			}
+		})
+	}
+}
@@ -1,43 +1,300 @@
 package sources
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"unicode"
 )
 
 const (
-	dateTextSeparator  = ":"
-	multiDateSeparator = "/"
+	dateTextSeparator  = ':'
+	multiDateSeparator = '/'
+	commentMarker      = '#'
+	quote              = '"'
+	escape             = '\\'
 )
 
 // RawTask represents an unprocessed task parsed from a line of an input source file
 type RawTask struct {
 	Date string
 	Text string
+
+	// Column is the (1-indexed) column of the date in its line, used for error reporting
+	Column int
+	// ID is the identifier of the line, which is shared by the tasks of a line with multiple dates
+	ID string
+	// Header holds the settings of the source file from which the task was loaded, if any
+	Header *Header
+
+	// Path and Line locate the line of the source file from which the task was loaded, which is Raw
+	Path string
+	Line int
+	Raw  string
+
+	// Reminders are the numbers of days before each occurrence of the task on which it is reminded
+	Reminders []int
+	// Label is the label by which relative tasks refer to the task
+	Label string
+}
+
+// ParseError is an error encountered while parsing a line, reporting the (1-indexed) column and,
+// when parsing an entire file, line at which it occurred
+type ParseError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("column %d: %s", e.Column, e.Msg)
+}
+
+// Line is a tokenized line from an input source file
+type Line struct {
+	Dates   []string
+	Text    string
+	Comment string
+	// Reminders are the lead times in days of the line's reminder marker, from the longest
+	Reminders []int
+	// Label is the label of the line's label marker
+	Label string
+
+	// DateColumns are the (1-indexed) columns at which each date starts
+	DateColumns []int
 }
 
 // ParseLine parses a line from an input source file into a slice of one or more RawLines
 func ParseLine(line string) ([]*RawTask, error) {
 	rts := []*RawTask{}
 
-	parts := strings.SplitN(line, dateTextSeparator, 2)
-	if len(parts) != 2 {
-		return rts, fmt.Errorf("invalid line [%s]", line)
+	l, err := Tokenize(line)
+	if err != nil {
+		return rts, fmt.Errorf("invalid line [%s]: %w", line, err)
 	}
 
-	dateParts := strings.Split(parts[0], multiDateSeparator)
-	text := cleanString(parts[1])
-
-	for _, date := range dateParts {
+	for i, date := range l.Dates {
 		rt := &RawTask{
-			Date: cleanString(date),
-			Text: text,
+			Date:      date,
+			Text:      l.Text,
+			Column:    l.DateColumns[i],
+			Reminders: l.Reminders,
+			Label:     l.Label,
 		}
 		rts = append(rts, rt)
 	}
 	return rts, nil
 }
 
+// Tokenize splits a line from an input source file into its dates, markers, text, and trailing comment.
+//
+// The dates are separated from the text by the first unescaped colon and from each other by
+// unescaped forward slashes, and any date can be followed by reminder and label markers that apply to
+// every date of the line, such as "Jan 12 [remind 7d,1d] [label bday]". The text can be wrapped in
+// double quotes so that it can contain any character, in which case a backslash escapes a quote or
+// backslash. Otherwise the text is kept as written, including any quotes and backslashes. A backslash
+// escapes the character following it in the dates. A comment starts with a # at the beginning of the
+// line or a # preceded and followed by whitespace.
+func Tokenize(line string) (*Line, error) {
+	runes := []rune(line)
+	l := &Line{Dates: []string{}}
+
+	i := skipSpace(runes, 0)
+	if i == len(runes) || runes[i] == commentMarker {
+		return l, &ParseError{Column: i + 1, Msg: "line does not contain a task"}
+	}
+
+	// dates
+	var date strings.Builder
+	dateColumn := i + 1
+	dateStarted := false
+	separated := false
+	for ; i < len(runes); i++ {
+		r := runes[i]
+		if !dateStarted && !unicode.IsSpace(r) {
+			dateColumn = i + 1
+			dateStarted = true
+		}
+		if r == escape && i+1 < len(runes) {
+			i++
+			date.WriteRune(runes[i])
+			continue
+		}
+		if isCommentStart(runes, i) {
+			break
+		}
+		if r == multiDateSeparator {
+			l.Dates = append(l.Dates, cleanString(date.String()))
+			l.DateColumns = append(l.DateColumns, dateColumn)
+			date.Reset()
+			dateColumn = i + 2
+			dateStarted = false
+			continue
+		}
+		if r == dateTextSeparator {
+			separated = true
+			i++
+			break
+		}
+		date.WriteRune(r)
+	}
+	if !separated {
+		return l, &ParseError{Column: i + 1, Msg: "missing date-text separator ':'"}
+	}
+	l.Dates = append(l.Dates, cleanString(date.String()))
+	l.DateColumns = append(l.DateColumns, dateColumn)
+
+	// markers
+	for j, d := range l.Dates {
+		trimmed, markers, err := splitMarkers(d)
+		if err != nil {
+			var perr *ParseError
+			if errors.As(err, &perr) {
+				perr.Column += l.DateColumns[j]
+			}
+			return l, err
+		}
+		l.Dates[j] = trimmed
+		for _, m := range markers {
+			err := l.setMarker(m)
+			if err != nil {
+				return l, &ParseError{Column: l.DateColumns[j] + m.index, Msg: err.Error()}
+			}
+		}
+	}
+
+	// text
+	i = skipSpace(runes, i)
+	if text, end, ok := quotedText(runes, i); ok {
+		l.Text = text
+		i = end
+	} else {
+		start := i
+		for ; i < len(runes); i++ {
+			if isCommentStart(runes, i) {
+				break
+			}
+		}
+		l.Text = cleanString(string(runes[start:i]))
+	}
+
+	// comment
+	if i < len(runes) {
+		l.Comment = cleanString(string(runes[i+1:]))
+	}
+	return l, nil
+}
+
+// quotedText parses text starting at an index that is entirely wrapped in double quotes, returning the
+// unquoted text and the index following it, which is the end of the line or the start of a comment, and
+// whether the text is quoted
+func quotedText(runes []rune, i int) (string, int, bool) {
+	if i == len(runes) || runes[i] != quote {
+		return "", i, false
+	}
+	var text strings.Builder
+	for i++; i < len(runes); i++ {
+		r := runes[i]
+		if r == escape && i+1 < len(runes) && (runes[i+1] == quote || runes[i+1] == escape) {
+			i++
+			text.WriteRune(runes[i])
+			continue
+		}
+		if r == quote {
+			end := skipSpace(runes, i+1)
+			if end < len(runes) && !isCommentStart(runes, end) {
+				return "", i, false
+			}
+			return text.String(), end, true
+		}
+		text.WriteRune(r)
+	}
+	return "", i, false
+}
+
+// setMarker sets the reminders or label of a line from a marker
+func (l *Line) setMarker(m *marker) error {
+	switch m.keyword {
+	case reminderKeyword:
+		if l.Reminders != nil {
+			return errors.New("line has more than one reminder marker")
+		}
+		reminders, err := parseReminders(m.value)
+		if err != nil {
+			return err
+		}
+		l.Reminders = reminders
+	case labelKeyword:
+		if l.Label != "" {
+			return errors.New("line has more than one label marker")
+		}
+		label, err := parseLabel(m.value)
+		if err != nil {
+			return err
+		}
+		l.Label = label
+	default:
+		return fmt.Errorf("unknown marker [%s], must be one of [%s, %s]", m.keyword, reminderKeyword, labelKeyword)
+	}
+	return nil
+}
+
+// IsComment reports whether a line contains only a comment
+func IsComment(line string) bool {
+	return strings.HasPrefix(cleanString(line), string(commentMarker))
+}
+
+// isCommentStart reports whether the rune at an index starts a comment: a # at the start of the
+// line or preceded by whitespace, and followed by whitespace or the end of the line
+func isCommentStart(runes []rune, i int) bool {
+	if runes[i] != commentMarker {
+		return false
+	}
+	if i > 0 && !unicode.IsSpace(runes[i-1]) {
+		return false
+	}
+	return i+1 == len(runes) || unicode.IsSpace(runes[i+1])
+}
+
+func skipSpace(runes []rune, i int) int {
+	for i < len(runes) && unicode.IsSpace(runes[i]) {
+		i++
+	}
+	return i
+}
+
+// stripComment removes a trailing comment from a line, following the same rules as Tokenize so that
+// a # that is escaped in the dates or within quoted text does not start a comment
+func stripComment(line string) string {
+	runes := []rune(line)
+	separated := false
+	for i := 0; i < len(runes); i++ {
+		if !separated && runes[i] == escape && i+1 < len(runes) {
+			i++
+			continue
+		}
+		if isCommentStart(runes, i) {
+			return string(runes[:i])
+		}
+		if !separated && runes[i] == dateTextSeparator {
+			separated = true
+			if _, end, ok := quotedText(runes, skipSpace(runes, i+1)); ok {
+				return string(runes[:end])
+			}
+		}
+	}
+	return line
+}
+
+// trailingComment returns the text of the trailing comment of a line
+func trailingComment(line string) string {
+	comment := strings.TrimPrefix(line[len(stripComment(line)):], string(commentMarker))
+	return cleanString(comment)
+}
+
 func cleanString(s string) string {
 	return strings.TrimSpace(s)
 }
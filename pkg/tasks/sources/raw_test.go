@@ -1,6 +1,7 @@
 package sources
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"testing"
@@ -85,6 +86,100 @@ func TestParseLine(t *testing.T) {
 				},
 			},
 		},
+		"trailing comment": {
+			line: "mon: foo # bar",
+			expected: []*RawTask{
+				{
+					Date: "mon",
+					Text: "foo",
+				},
+			},
+		},
+		"hash without surrounding whitespace is not a comment": {
+			line: "mon: call #5 re: bill#2",
+			expected: []*RawTask{
+				{
+					Date: "mon",
+					Text: "call #5 re: bill#2",
+				},
+			},
+		},
+		"quoted text": {
+			line: "mon: \"foo # bar: baz\"  # comment",
+			expected: []*RawTask{
+				{
+					Date: "mon",
+					Text: "foo # bar: baz",
+				},
+			},
+		},
+		"quoted text with escaped quote": {
+			line: "mon: \"watch \\\"Dune\\\"\"",
+			expected: []*RawTask{
+				{
+					Date: "mon",
+					Text: "watch \"Dune\"",
+				},
+			},
+		},
+		"unquoted text with quotes": {
+			line: "mon: watch \"Dune\" tonight",
+			expected: []*RawTask{
+				{
+					Date: "mon",
+					Text: "watch \"Dune\" tonight",
+				},
+			},
+		},
+		"escaped separators in date": {
+			line: "a\\/b\\:c/d: foo",
+			expected: []*RawTask{
+				{
+					Date: "a/b:c",
+					Text: "foo",
+				},
+				{
+					Date: "d",
+					Text: "foo",
+				},
+			},
+		},
+		"backslashes in text are kept": {
+			line: "mon: back up C:\\Users \\# bar",
+			expected: []*RawTask{
+				{
+					Date: "mon",
+					Text: "back up C:\\Users \\# bar",
+				},
+			},
+		},
+		"text after quote is not quoted": {
+			line: "mon: \"Dune\" tonight",
+			expected: []*RawTask{
+				{
+					Date: "mon",
+					Text: "\"Dune\" tonight",
+				},
+			},
+		},
+		"unterminated quote is not quoted": {
+			line: "mon: \"foo",
+			expected: []*RawTask{
+				{
+					Date: "mon",
+					Text: "\"foo",
+				},
+			},
+		},
+		"quoted text keeps backslashes that do not escape": {
+			line: "mon: \"C:\\Users\"",
+			expected: []*RawTask{
+				{
+					Date: "mon",
+					Text: "C:\\Users",
+				},
+			},
+		},
 		"valid with multiple dates and spaces": {
 			line: "date1 / date2 / date3:woo",
 			expected: []*RawTask{
@@ -147,6 +242,12 @@ func TestLoadLineError(t *testing.T) {
 		"no date-text separator multiple strings": {
 			line: "foobar xyz aaabbb",
 		},
+		"comment only": {
+			line: "# mon: foo",
+		},
+		"separator in comment": {
+			line: "mon # foo: bar",
+		},
 	}
 
 	for name, test := range tests {
@@ -160,6 +261,215 @@ func TestLoadLineError(t *testing.T) {
 	}
 }
 
+func TestTokenizeErrorColumn(t *testing.T) {
+	tests := map[string]struct {
+		line     string
+		expected int
+	}{
+		"missing separator": {
+			line:     "mon foo",
+			expected: 8,
+		},
+		"missing separator before comment": {
+			line:     "mon # foo: bar",
+			expected: 5,
+		},
+		"invalid reminder lead time": {
+			line:     "mon [remind 2x]: foo",
+			expected: 5,
+		},
+		"unterminated reminder marker": {
+			line:     "mon/tue [remind 2d: foo",
+			expected: 9,
+		},
+		"more than one reminder marker": {
+			line:     "mon [remind 1d]/tue [remind 2d]: foo",
+			expected: 21,
+		},
+		"more than one label marker": {
+			line:     "mon [label a] [label b]: foo",
+			expected: 15,
+		},
+		"invalid label": {
+			line:     "mon [label 1st]: foo",
+			expected: 5,
+		},
+		"unknown marker": {
+			line:     "mon [remind 1d] [repeat 2]: foo",
+			expected: 17,
+		},
+		"characters after marker": {
+			line:     "mon [label a] b: foo",
+			expected: 15,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			_, err := Tokenize(test.line)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected ParseError, got: %v", err)
+			}
+			if perr.Column != test.expected {
+				t.Fatalf("result column %d not equal to expected column %d", perr.Column, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseLineReminders(t *testing.T) {
+	tests := map[string]struct {
+		line      string
+		dates     []string
+		reminders []int
+	}{
+		"no marker": {
+			line:      "Jan 12: birthday",
+			dates:     []string{"Jan 12"},
+			reminders: nil,
+		},
+		"single lead time": {
+			line:      "Jan 12 [remind 3d]: birthday",
+			dates:     []string{"Jan 12"},
+			reminders: []int{3},
+		},
+		"lead times sorted and deduplicated": {
+			line:      "Jan 12 [Remind 1d, 1w, 7d]: birthday",
+			dates:     []string{"Jan 12"},
+			reminders: []int{7, 1},
+		},
+		"marker applies to every date": {
+			line:      "1/15 [remind 2d]: pay bills",
+			dates:     []string{"1", "15"},
+			reminders: []int{2},
+		},
+		"with label marker": {
+			line:      "Jan 12 [label bday] [remind 1d]: birthday",
+			dates:     []string{"Jan 12"},
+			reminders: []int{1},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result, err := ParseLine(test.line)
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if len(result) != len(test.dates) {
+				t.Fatalf("number of results %d not equal to expected number of results %d", len(result), len(test.dates))
+			}
+			for i, r := range result {
+				if r.Date != test.dates[i] {
+					t.Fatalf("result date %s not equal to expected date %s", r.Date, test.dates[i])
+				}
+				if fmt.Sprint(r.Reminders) != fmt.Sprint(test.reminders) {
+					t.Fatalf("result reminders %v not equal to expected reminders %v", r.Reminders, test.reminders)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenizeLabel(t *testing.T) {
+	l, err := Tokenize("Mar 1/Nov 1 [Label Smoke-Alarm]: change batteries")
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	if l.Label != "smoke-alarm" {
+		t.Fatalf("result label '%s' not equal to expected label '%s'", l.Label, "smoke-alarm")
+	}
+	if len(l.Dates) != 2 || l.Dates[1] != "Nov 1" {
+		t.Fatalf("result dates %v not equal to expected dates %v", l.Dates, []string{"Mar 1", "Nov 1"})
+	}
+}
+
+func TestTokenizeComment(t *testing.T) {
+	l, err := Tokenize("mon/tue: foo  # bar baz ")
+	if err != nil {
+		t.Fatalf("unexpected non-nil error: %v", err)
+	}
+	if l.Comment != "bar baz" {
+		t.Fatalf("result comment '%s' not equal to expected comment '%s'", l.Comment, "bar baz")
+	}
+}
+
+func TestStripComment(t *testing.T) {
+	tests := map[string]struct {
+		line            string
+		expected        string
+		expectedComment string
+	}{
+		"no comment": {
+			line:     "Mon: foo",
+			expected: "Mon: foo",
+		},
+		"trailing comment": {
+			line:            "Mon: foo # bar baz ",
+			expected:        "Mon: foo ",
+			expectedComment: "bar baz",
+		},
+		"marker without whitespace": {
+			line:     "Mon: learn C# and F#",
+			expected: "Mon: learn C# and F#",
+		},
+		"escaped marker in dates": {
+			line:            `Mon \# x: foo # bar`,
+			expected:        `Mon \# x: foo `,
+			expectedComment: "bar",
+		},
+		"quoted marker": {
+			line:     `Mon: "foo # bar"`,
+			expected: `Mon: "foo # bar"`,
+		},
+		"quoted marker with comment": {
+			line:            `Mon: "foo # bar" # baz`,
+			expected:        `Mon: "foo # bar" `,
+			expectedComment: "baz",
+		},
+		"marker after quote within text": {
+			line:            `Mon: say "foo # bar"`,
+			expected:        `Mon: say "foo `,
+			expectedComment: `bar"`,
+		},
+		"section header": {
+			line:            "[weekly] # work",
+			expected:        "[weekly] ",
+			expectedComment: "work",
+		},
+		"section header without comment": {
+			line:     "[weekly]",
+			expected: "[weekly]",
+		},
+		"comment line": {
+			line:            "# foo",
+			expected:        "",
+			expectedComment: "foo",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result := stripComment(test.line)
+			if result != test.expected {
+				t.Fatalf("result '%s' not equal to expected '%s'", result, test.expected)
+			}
+			comment := trailingComment(test.line)
+			if comment != test.expectedComment {
+				t.Fatalf("result comment '%s' not equal to expected comment '%s'", comment, test.expectedComment)
+			}
+			// tasks are split into the same comment when they are tokenized
+			if l, err := Tokenize(test.line); err == nil && l.Comment != test.expectedComment {
+				t.Fatalf("tokenized comment '%s' not equal to expected comment '%s'", l.Comment, test.expectedComment)
+			}
+		})
+	}
+}
+
 // testRawTaskSortKey creates a string for sorting RawTasks
 func testRawTaskSortKey(r *RawTask) string {
 	return fmt.Sprintf("Date-%s-Text-%s", r.Date, r.Text)
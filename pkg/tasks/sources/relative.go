@@ -0,0 +1,146 @@
+package sources
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// keyword of the marker that follows a date to set the label by which relative tasks refer to a task
+const labelKeyword = "label"
+
+// words of the dates of relative tasks that place them before or after the tasks to which they refer
+const (
+	relativeBefore = "before"
+	relativeAfter  = "after"
+)
+
+// Anchor is a task to which a relative task refers
+type Anchor interface {
+	DaysFrom(time.Time) int
+}
+
+// Relative represents a task that occurs a number of days before or after each occurrence of the tasks
+// with a label
+type Relative struct {
+	meta
+
+	interval int
+	weeks    bool
+	before   bool
+	ref      string
+	anchor   Anchor
+	text     string
+}
+
+// NewRelative constructs a Relative from a date of the form "<n>d before <label>" or "<n>d after <label>",
+// with the interval also written in weeks. The Relative does not occur until it is resolved to a task
+// with the label.
+func NewRelative(raw *RawTask) (*Relative, error) {
+	fields := strings.Fields(strings.ToLower(raw.Date))
+	if !isRelativeDate(raw.Date) {
+		return &Relative{}, fmt.Errorf("invalid relative date [%s]", raw.Date)
+	}
+
+	interval, weeks, err := parseInterval(strings.Join(fields[:len(fields)-2], ""))
+	if err != nil {
+		return &Relative{}, fmt.Errorf("invalid relative date [%s]: %v", raw.Date, err)
+	}
+	ref, err := parseLabel(fields[len(fields)-1])
+	if err != nil {
+		return &Relative{}, fmt.Errorf("invalid relative date [%s]: %v", raw.Date, err)
+	}
+
+	r := &Relative{
+		interval: interval,
+		weeks:    weeks,
+		before:   fields[len(fields)-2] == relativeBefore,
+		ref:      ref,
+		text:     raw.Text,
+		meta:     newMeta(raw, TypeRelative),
+	}
+	return r, nil
+}
+
+// isRelativeDate reports whether a date is that of a relative task
+func isRelativeDate(date string) bool {
+	fields := strings.Fields(strings.ToLower(date))
+	if len(fields) < 3 {
+		return false
+	}
+	placement := fields[len(fields)-2]
+	return placement == relativeBefore || placement == relativeAfter
+}
+
+// parseLabel parses the label of a label marker or of the date of a relative task, which is a word of
+// letters, digits, dashes, and underscores starting with a letter, compared without case
+func parseLabel(s string) (string, error) {
+	label := strings.ToLower(strings.TrimSpace(s))
+	if label == "" {
+		return "", fmt.Errorf("label marker must have a label")
+	}
+	for i, r := range label {
+		if (i == 0 && !unicode.IsLetter(r)) || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_') {
+			return "", fmt.Errorf("invalid label [%s], must be a word of letters, digits, dashes, and underscores starting with a letter", s)
+		}
+	}
+	return label, nil
+}
+
+// formatLabel writes the label marker of a label
+func formatLabel(label string) string {
+	return fmt.Sprintf("[%s %s]", labelKeyword, label)
+}
+
+// Ref returns the label of the tasks to which the task refers
+func (r *Relative) Ref() string {
+	return r.ref
+}
+
+// Offset returns the number of days from an occurrence of the task to which the task refers to the
+// occurrence of the task, which is negative for a task before the task to which it refers
+func (r *Relative) Offset() int {
+	if r.before {
+		return -r.interval
+	}
+	return r.interval
+}
+
+// Resolve returns a copy of the task that occurs relative to a task to which it refers
+func (r *Relative) Resolve(anchor Anchor) *Relative {
+	resolved := *r
+	resolved.anchor = anchor
+	return &resolved
+}
+
+// DaysFrom calculates the number of days until the task occurs relative to the task to which it was
+// resolved, which is zero for a task that has not been resolved
+func (r *Relative) DaysFrom(t time.Time) int {
+	if r.anchor == nil {
+		return 0
+	}
+	return r.anchor.DaysFrom(t.AddDate(0, 0, -r.Offset()))
+}
+
+// Type returns the type of the task
+func (r *Relative) Type() Type {
+	return TypeRelative
+}
+
+// Date returns the task's date in canonical form
+func (r *Relative) Date() string {
+	interval := fmt.Sprintf("%dd", r.interval)
+	if r.weeks {
+		interval = fmt.Sprintf("%dw", r.interval/7)
+	}
+	placement := relativeAfter
+	if r.before {
+		placement = relativeBefore
+	}
+	return fmt.Sprintf("%s %s %s", interval, placement, r.ref)
+}
+
+func (r *Relative) String() string {
+	return r.text
+}
@@ -0,0 +1,150 @@
+package sources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelativeDaysFrom(t *testing.T) {
+	// a Wednesday
+	now := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		date     string
+		anchor   string
+		expected int
+	}{
+		"after": {
+			date:     "2d after x",
+			anchor:   "Tue",
+			expected: 1,
+		},
+		"before": {
+			date:     "3d before x",
+			anchor:   "Mar 15 2024",
+			expected: 6,
+		},
+		"before occurrence of anchor that is after the next": {
+			date:     "3 days before x",
+			anchor:   "Fri",
+			expected: 6,
+		},
+		"in weeks": {
+			date:     "1w after x",
+			anchor:   "Mar 1 2024",
+			expected: 2,
+		},
+		"past": {
+			date:     "1d after x",
+			anchor:   "Mar 1 2024",
+			expected: -4,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			r, err := NewRelative(&RawTask{Date: test.date})
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			anchor, err := newRule(TypeAuto, &RawTask{Date: test.anchor})
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			result := r.Resolve(anchor).DaysFrom(now)
+			if result != test.expected {
+				t.Fatalf("result days %d not equal to expected days %d", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewRelative(t *testing.T) {
+	tests := map[string]struct {
+		raw            *RawTask
+		expectedDate   string
+		expectedOffset int
+	}{
+		"days after": {
+			raw: &RawTask{
+				Date: "2d after review",
+				Text: "follow up",
+			},
+			expectedDate:   "2d after review",
+			expectedOffset: 2,
+		},
+		"weeks before spelled out": {
+			raw: &RawTask{
+				Date: "1 Week Before Smoke-Alarm",
+				Text: "buy supplies",
+			},
+			expectedDate:   "1w before smoke-alarm",
+			expectedOffset: -7,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result, err := NewRelative(test.raw)
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if result.text != test.raw.Text {
+				t.Fatalf("result text '%s' not equal to expected text '%s'", result.text, test.raw.Text)
+			}
+			if result.Date() != test.expectedDate {
+				t.Fatalf("result date '%s' not equal to expected date '%s'", result.Date(), test.expectedDate)
+			}
+			if result.Offset() != test.expectedOffset {
+				t.Fatalf("result offset %d not equal to expected offset %d", result.Offset(), test.expectedOffset)
+			}
+		})
+	}
+}
+
+func TestNewRelativeError(t *testing.T) {
+	tests := map[string]struct {
+		raw *RawTask
+	}{
+		"empty": {
+			raw: &RawTask{},
+		},
+		"missing label": {
+			raw: &RawTask{
+				Date: "2d after",
+			},
+		},
+		"missing interval": {
+			raw: &RawTask{
+				Date: "after review",
+			},
+		},
+		"zero interval": {
+			raw: &RawTask{
+				Date: "0d after review",
+			},
+		},
+		"unknown unit": {
+			raw: &RawTask{
+				Date: "2 months after review",
+			},
+		},
+		"invalid label": {
+			raw: &RawTask{
+				Date: "2d after #review",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			_, err := NewRelative(test.raw)
+			if err == nil {
+				t.Fatal("unexpected nil error")
+			}
+		})
+	}
+}
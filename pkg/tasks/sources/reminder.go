@@ -0,0 +1,42 @@
+package sources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// keyword of the marker that follows a date to set the lead times of reminders of a task
+const reminderKeyword = "remind"
+
+// parseReminders parses the comma-separated lead times of a reminder marker of the form
+// "[remind <lead>,...]", returning the lead times in days from the longest. Each lead time is a number of
+// days or weeks, such as 7d or 1w.
+func parseReminders(leads string) ([]int, error) {
+	if strings.TrimSpace(leads) == "" {
+		return nil, fmt.Errorf("reminder marker must have at least one lead time")
+	}
+	reminders := []int{}
+	seen := make(map[int]bool)
+	for _, lead := range strings.Split(leads, ",") {
+		days, _, err := parseInterval(strings.TrimSpace(lead))
+		if err != nil {
+			return nil, fmt.Errorf("invalid reminder lead time [%s]: %v", strings.TrimSpace(lead), err)
+		}
+		if !seen[days] {
+			seen[days] = true
+			reminders = append(reminders, days)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(reminders)))
+	return reminders, nil
+}
+
+// formatReminders writes the reminder marker of lead times in days
+func formatReminders(reminders []int) string {
+	leads := []string{}
+	for _, days := range reminders {
+		leads = append(leads, fmt.Sprintf("%dd", days))
+	}
+	return fmt.Sprintf("[%s %s]", reminderKeyword, strings.Join(leads, ","))
+}
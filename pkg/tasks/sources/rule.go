@@ -0,0 +1,90 @@
+package sources
+
+import "time"
+
+// rule is implemented by each type of task
+type rule interface {
+	Type() Type
+	Date() string
+	DaysFrom(time.Time) int
+	String() string
+
+	// sortKey orders the dates of tasks of the same type
+	sortKey() int
+}
+
+// newRule constructs a task of the specified type, detecting the type from the date for TypeAuto
+func newRule(typ Type, raw *RawTask) (rule, error) {
+	if typ == TypeAuto {
+		var err error
+		typ, err = DetectType(raw.Date)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch typ {
+	case TypeWeekly:
+		return NewWeekly(raw)
+	case TypeMonthly:
+		return NewMonthly(raw)
+	case TypeAnnual:
+		return NewAnnual(raw)
+	case TypeFloating:
+		return NewFloating(raw)
+	case TypeDeadline:
+		return NewDeadline(raw)
+	case TypeRelative:
+		return NewRelative(raw)
+	default:
+		return NewSingle(raw)
+	}
+}
+
+// typeRank orders tasks of different types
+func typeRank(typ Type) int {
+	switch typ {
+	case TypeWeekly:
+		return 0
+	case TypeMonthly:
+		return 1
+	case TypeAnnual:
+		return 2
+	case TypeSingle:
+		return 3
+	case TypeFloating:
+		return 4
+	case TypeDeadline:
+		return 5
+	default:
+		return 6
+	}
+}
+
+func (w *Weekly) sortKey() int {
+	return int(w.day)
+}
+
+func (m *Monthly) sortKey() int {
+	return m.day
+}
+
+func (a *Annual) sortKey() int {
+	return int(a.month)*100 + a.day
+}
+
+func (s *Single) sortKey() int {
+	return s.year*10000 + int(s.month)*100 + s.day
+}
+
+func (f *Floating) sortKey() int {
+	return f.interval
+}
+
+func (d *Deadline) sortKey() int {
+	return d.due.sortKey()
+}
+
+func (r *Relative) sortKey() int {
+	return r.Offset()
+}
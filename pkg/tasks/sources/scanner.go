@@ -0,0 +1,107 @@
+package sources
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Scanner reads logical lines from an input source file, where a line ending with a backslash preceded
+// by whitespace is continued on the next line if it is indented
+type Scanner struct {
+	scanner *bufio.Scanner
+	text    string
+	raw     []string
+	line    int
+	read    int
+
+	// next is a line read ahead to check whether it continues the current line
+	next     string
+	haveNext bool
+}
+
+// NewScanner constructs a Scanner
+func NewScanner(r io.Reader) *Scanner {
+	return &Scanner{
+		scanner: bufio.NewScanner(r),
+	}
+}
+
+// Scan advances to the next logical line, returning false when there are no more lines
+func (s *Scanner) Scan() bool {
+	line, ok := s.readLine()
+	if !ok {
+		return false
+	}
+	s.line = s.read
+	s.raw = []string{line}
+
+	var text strings.Builder
+	for !IsComment(line) && isContinued(line) {
+		next, ok := s.readLine()
+		if !ok {
+			break
+		}
+		if !isIndented(next) {
+			s.next, s.haveNext = next, true
+			s.read--
+			break
+		}
+		s.raw = append(s.raw, next)
+		text.WriteString(line[:len(line)-1])
+		// indentation of a continuation line is not part of the logical line
+		line = strings.TrimLeft(next, " \t")
+	}
+	text.WriteString(line)
+	s.text = text.String()
+	return true
+}
+
+// readLine returns the next physical line, which is the line read ahead if there is one
+func (s *Scanner) readLine() (string, bool) {
+	s.read++
+	if s.haveNext {
+		s.haveNext = false
+		return s.next, true
+	}
+	if !s.scanner.Scan() {
+		s.read--
+		return "", false
+	}
+	return s.scanner.Text(), true
+}
+
+// Text returns the current logical line
+func (s *Scanner) Text() string {
+	return s.text
+}
+
+// Raw returns the physical lines making up the current logical line
+func (s *Scanner) Raw() []string {
+	return s.raw
+}
+
+// Line returns the (1-indexed) line number at which the current logical line starts
+func (s *Scanner) Line() int {
+	return s.line
+}
+
+// Err returns the first non-EOF error encountered by the Scanner
+func (s *Scanner) Err() error {
+	return s.scanner.Err()
+}
+
+// isContinued reports whether a line ends with a single backslash preceded by whitespace
+func isContinued(line string) bool {
+	trimmed := strings.TrimSuffix(line, string(escape))
+	if trimmed == line || trimmed == "" {
+		return false
+	}
+	return unicode.IsSpace(rune(trimmed[len(trimmed)-1]))
+}
+
+// isIndented reports whether a line starts with whitespace and is not blank
+func isIndented(line string) bool {
+	return cleanString(line) != "" && (line[0] == ' ' || line[0] == '\t')
+}
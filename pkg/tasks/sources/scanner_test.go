@@ -0,0 +1,79 @@
+package sources
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanner(t *testing.T) {
+	tests := map[string]struct {
+		input         string
+		expectedText  []string
+		expectedLines []int
+	}{
+		"no continuations": {
+			input:         "a\nb\n\nc",
+			expectedText:  []string{"a", "b", "", "c"},
+			expectedLines: []int{1, 2, 3, 4},
+		},
+		"continuation": {
+			input:         "mon: foo \\\n    bar\ntue: baz",
+			expectedText:  []string{"mon: foo bar", "tue: baz"},
+			expectedLines: []int{1, 3},
+		},
+		"multiple continuations": {
+			input:         "mon: \\\n  foo \\\n\tbar\ntue: baz",
+			expectedText:  []string{"mon: foo bar", "tue: baz"},
+			expectedLines: []int{1, 4},
+		},
+		"escaped backslash is not a continuation": {
+			input:         "mon: foo \\\\\ntue: baz",
+			expectedText:  []string{"mon: foo \\\\", "tue: baz"},
+			expectedLines: []int{1, 2},
+		},
+		"comment is not continued": {
+			input:         "# foo \\\nmon: bar",
+			expectedText:  []string{"# foo \\", "mon: bar"},
+			expectedLines: []int{1, 2},
+		},
+		"continuation on last line": {
+			input:         "mon: foo \\",
+			expectedText:  []string{"mon: foo \\"},
+			expectedLines: []int{1},
+		},
+		"backslash without preceding whitespace is not a continuation": {
+			input:         "mon: C:\\\n  tue: baz",
+			expectedText:  []string{"mon: C:\\", "  tue: baz"},
+			expectedLines: []int{1, 2},
+		},
+		"next line not indented is not a continuation": {
+			input:         "mon: foo \\\ntue: baz\nwed: qux",
+			expectedText:  []string{"mon: foo \\", "tue: baz", "wed: qux"},
+			expectedLines: []int{1, 2, 3},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			s := NewScanner(strings.NewReader(test.input))
+			text := []string{}
+			lines := []int{}
+			for s.Scan() {
+				text = append(text, s.Text())
+				lines = append(lines, s.Line())
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			if len(text) != len(test.expectedText) {
+				t.Fatalf("result lines %q not equal to expected lines %q", text, test.expectedText)
+			}
+			for i := range text {
+				if text[i] != test.expectedText[i] || lines[i] != test.expectedLines[i] {
+					t.Fatalf("result line %d '%s' not equal to expected line %d '%s'", lines[i], text[i], test.expectedLines[i], test.expectedText[i])
+				}
+			}
+		})
+	}
+}
@@ -11,14 +11,34 @@ import (
 
 // Single represents a single-occurrence task
 type Single struct {
+	meta
+
 	day   int
 	month time.Month
 	year  int
 	text  string
 }
 
+// format for single dates specified as YYYY-MM-DD
+const isoDateFormat = "2006-01-02"
+
 // NewSingle constructs a Single
 func NewSingle(raw *RawTask) (*Single, error) {
+	if isoDate.MatchString(raw.Date) {
+		date, err := time.Parse(isoDateFormat, raw.Date)
+		if err != nil {
+			return &Single{}, fmt.Errorf("could not parse date: %v", err)
+		}
+		s := &Single{
+			day:   date.Day(),
+			month: date.Month(),
+			year:  date.Year(),
+			text:  raw.Text,
+			meta:  newMeta(raw, TypeSingle),
+		}
+		return s, nil
+	}
+
 	dateParts := strings.SplitN(raw.Date, " ", 3)
 	if len(dateParts) != 3 {
 		return &Single{}, fmt.Errorf("invalid single date [%s]", raw.Date)
@@ -26,7 +46,7 @@ func NewSingle(raw *RawTask) (*Single, error) {
 
 	month, err := calendar.ParseMonth(dateParts[0])
 	if err != nil {
-		return &Single{}, fmt.Errorf("invalid single date [%s]", raw.Date)
+		return &Single{}, fmt.Errorf("invalid single date [%s]: %v", raw.Date, err)
 	}
 	day, err := strconv.ParseInt(dateParts[1], 10, 0)
 	if err != nil {
@@ -48,17 +68,38 @@ func NewSingle(raw *RawTask) (*Single, error) {
 		month: month,
 		year:  int(year),
 		text:  raw.Text,
+		meta:  newMeta(raw, TypeSingle),
 	}
 	return s, nil
 }
 
 // DaysFrom calculates the number of days until a task's date
 func (s *Single) DaysFrom(t time.Time) int {
+	t = s.in(t)
 	sTime := time.Date(s.year, s.month, s.day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
 	days := calendar.UnixToDaysFloored(sTime.Unix() - t.Unix())
 	return int(days)
 }
 
+// Type returns the type of the task
+func (s *Single) Type() Type {
+	return TypeSingle
+}
+
+// Date returns the task's date in canonical form
+func (s *Single) Date() string {
+	return fmt.Sprintf("%s %d %d", s.month.String()[:3], s.day, s.year)
+}
+
+// Validate returns an error if the task's date does not exist (e.g., Feb 30 2023), which is not
+// rejected by NewSingle since DaysFrom rolls such a date over into the next month
+func (s *Single) Validate() error {
+	if !calendar.IsValidDate(s.year, s.month, s.day) {
+		return fmt.Errorf("date [%s] does not exist", s.Date())
+	}
+	return nil
+}
+
 func (s *Single) String() string {
 	return s.text
 }
@@ -149,6 +149,18 @@ func TestNewSingle(t *testing.T) {
 				text:  "foo bar woo",
 			},
 		},
+		"iso date": {
+			raw: &RawTask{
+				Date: "2021-04-17",
+				Text: "foo bar woo",
+			},
+			expected: &Single{
+				month: time.April,
+				day:   17,
+				year:  2021,
+				text:  "foo bar woo",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -174,6 +186,36 @@ func TestNewSingle(t *testing.T) {
 	}
 }
 
+func TestSingleDate(t *testing.T) {
+	tests := map[string]struct {
+		date     string
+		expected string
+	}{
+		"iso date": {
+			date:     "2024-03-03",
+			expected: "Mar 3 2024",
+		},
+		"month name": {
+			date:     "march 3 2024",
+			expected: "Mar 3 2024",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			r, err := NewSingle(&RawTask{Date: test.date})
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			result := r.Date()
+			if result != test.expected {
+				t.Fatalf("result date '%s' not equal to expected date '%s'", result, test.expected)
+			}
+		})
+	}
+}
+
 func TestNewSingleError(t *testing.T) {
 	tests := map[string]struct {
 		raw *RawTask
@@ -226,6 +268,11 @@ func TestNewSingleError(t *testing.T) {
 				Date: "April 0 2021",
 			},
 		},
+		"invalid iso date": {
+			raw: &RawTask{
+				Date: "2021-02-30",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -238,3 +285,37 @@ func TestNewSingleError(t *testing.T) {
 		})
 	}
 }
+
+func TestSingleValidate(t *testing.T) {
+	tests := map[string]struct {
+		s     *Single
+		valid bool
+	}{
+		"valid": {
+			s:     &Single{month: time.April, day: 30, year: 2023},
+			valid: true,
+		},
+		"leap day in leap year": {
+			s:     &Single{month: time.February, day: 29, year: 2024},
+			valid: true,
+		},
+		"leap day in non leap year": {
+			s:     &Single{month: time.February, day: 29, year: 2023},
+			valid: false,
+		},
+		"April 31": {
+			s:     &Single{month: time.April, day: 31, year: 2023},
+			valid: false,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			err := test.s.Validate()
+			if (err == nil) != test.valid {
+				t.Fatalf("result valid %t not equal to expected valid %t", err == nil, test.valid)
+			}
+		})
+	}
+}
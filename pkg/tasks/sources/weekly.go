@@ -9,6 +9,8 @@ import (
 
 // Weekly represents a weekly task
 type Weekly struct {
+	meta
+
 	day  time.Weekday
 	text string
 }
@@ -23,15 +25,27 @@ func NewWeekly(raw *RawTask) (*Weekly, error) {
 	w := &Weekly{
 		day:  day,
 		text: raw.Text,
+		meta: newMeta(raw, TypeWeekly),
 	}
 	return w, nil
 }
 
 // DaysFrom calculates the number of days until a task's date
 func (w *Weekly) DaysFrom(t time.Time) int {
+	t = w.in(t)
 	return calendar.DaysBetweenWeekdays(t.Weekday(), w.day)
 }
 
+// Type returns the type of the task
+func (w *Weekly) Type() Type {
+	return TypeWeekly
+}
+
+// Date returns the task's date in canonical form
+func (w *Weekly) Date() string {
+	return w.day.String()[:3]
+}
+
 func (w *Weekly) String() string {
 	return w.text
 }
@@ -77,6 +77,36 @@ func TestNewWeekly(t *testing.T) {
 	}
 }
 
+func TestWeeklyDate(t *testing.T) {
+	tests := map[string]struct {
+		date     string
+		expected string
+	}{
+		"full name": {
+			date:     "wednesday",
+			expected: "Wed",
+		},
+		"abbreviation": {
+			date:     "WED",
+			expected: "Wed",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			r, err := NewWeekly(&RawTask{Date: test.date})
+			if err != nil {
+				t.Fatalf("unexpected non-nil error: %v", err)
+			}
+			result := r.Date()
+			if result != test.expected {
+				t.Fatalf("result date '%s' not equal to expected date '%s'", result, test.expected)
+			}
+		})
+	}
+}
+
 func TestNewWeeklyError(t *testing.T) {
 	tests := map[string]struct {
 		raw *RawTask
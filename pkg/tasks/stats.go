@@ -0,0 +1,95 @@
+package tasks
+
+import "time"
+
+// maximum number of days between consecutive occurrences of a task for which Stats are calculated
+const maxDaysBetweenOccurrences = 31
+
+// Stats summarizes the completion of the recent occurrences of a recurring task
+type Stats struct {
+	Task Task
+	// History records whether each recent occurrence was completed, from the oldest to the newest
+	History []bool
+	// CurrentStreak is the number of consecutive completed occurrences up to the newest
+	CurrentStreak int
+	// LongestStreak is the largest number of consecutive completed occurrences
+	LongestStreak int
+}
+
+// NewStats calculates the Stats of the last n occurrences on or before today of the tasks loaded from a
+// source file line, which share an identifier. An occurrence today that has not been completed is not
+// counted since it can still be completed, but every earlier occurrence that was not completed is.
+// Occurrences are counted from the first completion of the task, since recurring tasks do not record when
+// they were added, so no occurrences are counted for a task that has never been completed.
+func NewStats(tsks []Task, today time.Time, n int, store CompletionStore) *Stats {
+	s := &Stats{
+		Task:    tsks[0],
+		History: []bool{},
+	}
+	first, ok := store.FirstDone(s.Task.ID())
+	if !ok {
+		return s
+	}
+
+	occurrences := []bool{}
+	for day := 0; day <= n*maxDaysBetweenOccurrences && len(occurrences) < n; day++ {
+		date := today.AddDate(0, 0, -day)
+		if daysBetween(first, date) < 0 {
+			break
+		}
+		if !occursOn(tsks, date) {
+			continue
+		}
+		done := store.Done(s.Task.ID(), date)
+		if day == 0 && !done {
+			continue
+		}
+		occurrences = append(occurrences, done)
+	}
+	for i := len(occurrences) - 1; i >= 0; i-- {
+		s.History = append(s.History, occurrences[i])
+	}
+
+	streak := 0
+	for _, done := range s.History {
+		if !done {
+			streak = 0
+			continue
+		}
+		streak++
+		if streak > s.LongestStreak {
+			s.LongestStreak = streak
+		}
+	}
+	s.CurrentStreak = streak
+	return s
+}
+
+// Completed returns the number of completed occurrences
+func (s *Stats) Completed() int {
+	completed := 0
+	for _, done := range s.History {
+		if done {
+			completed++
+		}
+	}
+	return completed
+}
+
+// Rate returns the fraction of occurrences that were completed
+func (s *Stats) Rate() float64 {
+	if len(s.History) == 0 {
+		return 0
+	}
+	return float64(s.Completed()) / float64(len(s.History))
+}
+
+// occursOn reports whether any of the tasks occurs on a date
+func occursOn(tsks []Task, date time.Time) bool {
+	for _, t := range tsks {
+		if t.DaysFrom(date) == 0 {
+			return true
+		}
+	}
+	return false
+}
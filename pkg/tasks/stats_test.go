@@ -0,0 +1,113 @@
+package tasks
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
+
+func TestStats(t *testing.T) {
+	// a Wednesday
+	today := time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)
+
+	newTask := func(date string) Task {
+		tsk, err := newDetectedTask(&sources.RawTask{Date: date, Text: "gym", ID: "gym"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tsk
+	}
+
+	tests := map[string]struct {
+		tasks           []Task
+		n               int
+		store           testStore
+		expected        string
+		expectedCurrent int
+		expectedLongest int
+	}{
+		"no completions": {
+			tasks:           []Task{newTask("Mon")},
+			n:               3,
+			store:           testStore{},
+			expected:        "[]",
+			expectedCurrent: 0,
+			expectedLongest: 0,
+		},
+		"occurrences before first completion are not counted": {
+			tasks:           []Task{newTask("Mon")},
+			n:               4,
+			store:           testStore{"gym|2024-02-26": true},
+			expected:        "[true false]",
+			expectedCurrent: 0,
+			expectedLongest: 1,
+		},
+		"current streak": {
+			tasks:           []Task{newTask("Mon")},
+			n:               4,
+			store:           testStore{"gym|2024-02-12": true, "gym|2024-02-26": true, "gym|2024-03-04": true},
+			expected:        "[true false true true]",
+			expectedCurrent: 2,
+			expectedLongest: 2,
+		},
+		"missed newest occurrence": {
+			tasks:           []Task{newTask("Mon")},
+			n:               4,
+			store:           testStore{"gym|2024-02-12": true, "gym|2024-02-19": true, "gym|2024-02-26": true},
+			expected:        "[true true true false]",
+			expectedCurrent: 0,
+			expectedLongest: 3,
+		},
+		"occurrence today not done is not counted": {
+			tasks:           []Task{newTask("Wed")},
+			n:               2,
+			store:           testStore{"gym|2024-02-21": true},
+			expected:        "[true false]",
+			expectedCurrent: 0,
+			expectedLongest: 1,
+		},
+		"occurrence today done is counted": {
+			tasks:           []Task{newTask("Wed")},
+			n:               2,
+			store:           testStore{"gym|2024-02-21": true, "gym|2024-03-06": true},
+			expected:        "[false true]",
+			expectedCurrent: 1,
+			expectedLongest: 1,
+		},
+		"line with multiple dates": {
+			tasks:           []Task{newTask("Mon"), newTask("Tue")},
+			n:               4,
+			store:           testStore{"gym|2024-02-26": true, "gym|2024-03-04": true, "gym|2024-03-05": true},
+			expected:        "[true false true true]",
+			expectedCurrent: 2,
+			expectedLongest: 2,
+		},
+		"monthly": {
+			tasks:           []Task{newTask("1")},
+			n:               3,
+			store:           testStore{"gym|2024-01-01": true, "gym|2024-03-01": true},
+			expected:        "[true false true]",
+			expectedCurrent: 1,
+			expectedLongest: 1,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			s := NewStats(test.tasks, today, test.n, test.store)
+			result := fmt.Sprint(s.History)
+			if result != test.expected {
+				t.Fatalf("result history %s not equal to expected history %s", result, test.expected)
+			}
+			if s.CurrentStreak != test.expectedCurrent {
+				t.Fatalf("result current streak %d not equal to expected current streak %d", s.CurrentStreak, test.expectedCurrent)
+			}
+			if s.LongestStreak != test.expectedLongest {
+				t.Fatalf("result longest streak %d not equal to expected longest streak %d", s.LongestStreak, test.expectedLongest)
+			}
+		})
+	}
+}
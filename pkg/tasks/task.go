@@ -1,9 +1,16 @@
 package tasks
 
-import "time"
+import (
+	"time"
+
+	"github.com/dkaslovsky/calendar-tasks/pkg/tasks/sources"
+)
 
 // Task represents a task to occur on a specified date(s)
 type Task interface {
 	DaysFrom(time.Time) int
+	ID() string
+	// Source returns where the task was loaded from
+	Source() sources.Source
 	String() string
 }